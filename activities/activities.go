@@ -6,4 +6,20 @@ package activities
 // each activity method can access through the receiver. In tests, stub fields
 // can be toggled to avoid real side effects like sending emails or calling
 // third-party APIs.
-type Activities struct{}
+type Activities struct {
+	// SentReminders deduplicates reminder delivery by idempotency key.
+	// When nil, every call to SendReminder sends.
+	SentReminders SentReminderStore
+
+	// Payments is the payment platform client. When nil, payment activities
+	// only log.
+	Payments PaymentsAPI
+
+	// Ops posts internal alerts to the support channel. When nil, NotifyOps
+	// only logs.
+	Ops *OpsAlerter
+
+	// StatusEvents receives the workflow's status changes. When nil,
+	// PublishStatusEvent only logs.
+	StatusEvents StatusPublisher
+}
@@ -0,0 +1,30 @@
+package activities
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// recordSupplierCall records a verification supplier call that started at
+// start and ended with result.
+func recordSupplierCall(ctx context.Context, start time.Time, result string) {
+	metrics := activity.GetMetricsHandler(ctx).WithTags(map[string]string{"result": result})
+	metrics.Timer(shared.MetricSupplierLatency).Record(time.Since(start))
+	metrics.Counter(shared.MetricSupplierRequests).Inc(1)
+}
+
+// recordReminderSent counts a delivered reminder.
+func recordReminderSent(ctx context.Context, req shared.ReminderRequest) {
+	channel := req.Channel
+	if channel == "" {
+		channel = shared.ChannelEmail
+	}
+	activity.GetMetricsHandler(ctx).WithTags(map[string]string{
+		"reminder_type": req.ReminderType,
+		"channel":       channel,
+	}).Counter(shared.MetricRemindersSent).Inc(1)
+}
@@ -10,19 +10,74 @@ import (
 )
 
 // SendReminder sends an onboarding reminder email to the merchant.
-// Idempotency: not naturally idempotent (retries would send duplicate emails).
-// In production, pass reminderID as an idempotency key to the email provider.
+// Idempotency: deduplicated by req.IdempotencyKey. Keys already recorded in
+// SentReminders are not sent again, so activity retries and workflow resets
+// deliver each reminder at most once. The key is also the one to pass to the
+// email provider, which covers a crash between sending and recording.
 func (a *Activities) SendReminder(ctx context.Context, req shared.ReminderRequest) (string, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Sending reminder",
-		"merchantId", req.MerchantID,
-		"reminderType", req.ReminderType,
-		"email", req.Email,
-	)
 
-	// In production: integrate with email service (SendGrid, SES, etc.)
+	if a.SentReminders != nil && req.IdempotencyKey != "" {
+		reminderID, found, err := a.SentReminders.Lookup(req.IdempotencyKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to check sent reminders: %w", err)
+		}
+		if found {
+			logger.Info("Reminder already sent, skipping",
+				"reminderType", req.ReminderType,
+				"reminderID", reminderID,
+			)
+			return reminderID, nil
+		}
+	}
+
 	reminderID := fmt.Sprintf("REMIND-%s-%s", req.MerchantID, req.ReminderType)
+	if req.Sequence > 0 {
+		// Delivery receipts must tell repeated sends apart.
+		reminderID += fmt.Sprintf("-%d", req.Sequence)
+	}
+	switch req.Channel {
+	case shared.ChannelSMS:
+		logger.Info("Sending reminder by SMS",
+			"reminderType", req.ReminderType,
+			"phone", req.Phone,
+			"idempotencyKey", req.IdempotencyKey,
+		)
+		// In production: integrate with SMS service (Twilio, MessageBird, etc.)
+		reminderID += "-sms"
+	default:
+		logger.Info("Sending reminder",
+			"reminderType", req.ReminderType,
+			"email", req.Email,
+			"idempotencyKey", req.IdempotencyKey,
+		)
+		// In production: integrate with email service (SendGrid, SES, etc.)
+	}
+
+	if a.SentReminders != nil && req.IdempotencyKey != "" {
+		if err := a.SentReminders.Record(req.IdempotencyKey, reminderID); err != nil {
+			return "", fmt.Errorf("failed to record sent reminder: %w", err)
+		}
+	}
 	logger.Info("Reminder sent successfully", "reminderID", reminderID)
+	recordReminderSent(ctx, req)
 
 	return reminderID, nil
 }
+
+// EscalateToAccountManager tells the merchant's account manager that the
+// merchant can no longer be reached on their primary channel, so a human can
+// follow up before the compliance deadline.
+// Idempotency: the workflow escalates at most once per onboarding; a retry
+// may repeat the notification, which is acceptable for an internal alert.
+func (a *Activities) EscalateToAccountManager(ctx context.Context, req shared.EscalationRequest) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Escalating unreachable merchant to account manager",
+		"accountManager", req.AccountManagerEmail,
+		"reason", req.Reason,
+		"fallbackChannel", req.FallbackChannel,
+	)
+
+	// In production: email the account manager and open a CRM task.
+	return nil
+}
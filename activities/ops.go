@@ -0,0 +1,371 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/activity"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// NotifyOps posts an internal alert to the support and account-management
+// chat channel when a merchant's onboarding ends badly.
+// Idempotency: not idempotent — a retry after a timeout may post twice,
+// which is acceptable for an internal alert.
+func (a *Activities) NotifyOps(ctx context.Context, alert shared.OpsAlert) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Notifying ops",
+		"outcome", alert.Outcome,
+		"reason", alert.Reason,
+	)
+
+	if a.Ops == nil {
+		// No webhook configured (local development).
+		return nil
+	}
+	return a.Ops.Notify(ctx, alert)
+}
+
+// OpsAlerter posts OpsAlerts to a chat-style incoming webhook (Slack,
+// Mattermost and compatible). At most MaxPerWindow alerts are posted
+// individually per Window; the rest are rolled up into a single digest
+// posted when the window ends, so a mass-expiry day doesn't flood the channel.
+//
+// Alerts held back for the digest are kept in the queue file opened with
+// OpenQueue, so they survive a restart, and Close posts them on shutdown.
+// The limit is per process: each activity worker posts up to MaxPerWindow
+// alerts of its own.
+type OpsAlerter struct {
+	WebhookURL   string
+	UIBaseURL    string // Temporal Web UI, used to link to the workflow.
+	MaxPerWindow int
+	Window       time.Duration
+	HTTPClient   *http.Client
+
+	mu          sync.Mutex
+	windowStart time.Time
+	sent        int
+	pending     []shared.OpsAlert
+	flushTimer  *time.Timer
+	queue       *os.File
+	flushing    sync.Mutex // Serializes digests.
+}
+
+// NewOpsAlerter returns an OpsAlerter with default rate limits: 5 individual
+// alerts per 10 minutes.
+func NewOpsAlerter(webhookURL, uiBaseURL string) *OpsAlerter {
+	return &OpsAlerter{
+		WebhookURL:   webhookURL,
+		UIBaseURL:    uiBaseURL,
+		MaxPerWindow: 5,
+		Window:       10 * time.Minute,
+		HTTPClient:   &http.Client{Timeout: 5 * time.Second},
+	}
+}
+
+// OpenQueue keeps the alerts queued for the digest in an append-only JSON
+// Lines file at path. Alerts left in it by a previous process are posted in
+// a digest at the end of the first window.
+func (o *OpsAlerter) OpenQueue(path string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return fmt.Errorf("failed to create ops alert queue directory: %w", err)
+		}
+	}
+	pending, err := loadOpsAlertQueue(path)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open ops alert queue: %w", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.queue = f
+	o.pending = append(pending, o.pending...)
+	if len(o.pending) > 0 && o.flushTimer == nil {
+		o.flushTimer = time.AfterFunc(o.Window, o.flush)
+	}
+	return nil
+}
+
+// loadOpsAlertQueue reads the queued alerts. A torn final line is truncated,
+// like in FileSentReminderStore; its Notify call failed and was retried.
+func loadOpsAlertQueue(path string) ([]shared.OpsAlert, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ops alert queue: %w", err)
+	}
+
+	var pending []shared.OpsAlert
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	for _, line := range bytes.Split(data[:complete], []byte("\n")) {
+		var alert shared.OpsAlert
+		if len(line) == 0 || json.Unmarshal(line, &alert) != nil {
+			continue
+		}
+		pending = append(pending, alert)
+	}
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, fmt.Errorf("failed to truncate torn ops alert queue entry: %w", err)
+		}
+	}
+	return pending, nil
+}
+
+// Notify posts alert immediately if the current window has capacity, or
+// queues it for the end-of-window digest otherwise. A queued alert is
+// written to the queue file before Notify returns.
+func (o *OpsAlerter) Notify(ctx context.Context, alert shared.OpsAlert) error {
+	o.mu.Lock()
+	now := time.Now()
+	if o.windowStart.IsZero() || now.Sub(o.windowStart) >= o.Window {
+		o.windowStart = now
+		o.sent = 0
+	}
+	if o.sent >= o.MaxPerWindow {
+		defer o.mu.Unlock()
+		if err := o.appendQueue(alert); err != nil {
+			return err
+		}
+		o.pending = append(o.pending, alert)
+		if o.flushTimer == nil {
+			o.flushTimer = time.AfterFunc(o.windowStart.Add(o.Window).Sub(now), o.flush)
+		}
+		return nil
+	}
+	// Reserve the slot so concurrent calls can't overrun the window, and give
+	// it back if the post fails: the retry shouldn't count twice.
+	o.sent++
+	window := o.windowStart
+	o.mu.Unlock()
+
+	if err := o.post(ctx, o.alertMessage(alert)); err != nil {
+		o.mu.Lock()
+		if o.windowStart.Equal(window) && o.sent > 0 {
+			o.sent--
+		}
+		o.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// appendQueue writes alert to the queue file. o.mu must be held.
+func (o *OpsAlerter) appendQueue(alert shared.OpsAlert) error {
+	if o.queue == nil {
+		return nil
+	}
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	if _, err := o.queue.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write ops alert queue: %w", err)
+	}
+	if err := o.queue.Sync(); err != nil {
+		return fmt.Errorf("failed to sync ops alert queue: %w", err)
+	}
+	return nil
+}
+
+// flush posts the digest of alerts queued during the last window and drops
+// them from the queue file. If posting fails they stay queued and are
+// retried at the end of the next window.
+func (o *OpsAlerter) flush() {
+	o.mu.Lock()
+	o.flushTimer = nil
+	o.mu.Unlock()
+
+	if err := o.postDigest(context.Background()); err != nil {
+		log.Printf("Failed to post ops alert digest, retrying in %s: %v", o.Window, err)
+		o.mu.Lock()
+		if o.flushTimer == nil {
+			o.flushTimer = time.AfterFunc(o.Window, o.flush)
+		}
+		o.mu.Unlock()
+	}
+}
+
+// postDigest posts the queued alerts as one digest and removes them from the
+// queue. Alerts queued while the digest is being posted stay queued.
+func (o *OpsAlerter) postDigest(ctx context.Context) error {
+	o.flushing.Lock()
+	defer o.flushing.Unlock()
+
+	o.mu.Lock()
+	pending := o.pending
+	o.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil
+	}
+	if err := o.post(ctx, o.digestMessage(pending)); err != nil {
+		return fmt.Errorf("%d alerts: %w", len(pending), err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending = o.pending[len(pending):]
+	return o.rewriteQueue()
+}
+
+// rewriteQueue replaces the queue file's contents with o.pending. o.mu must
+// be held.
+func (o *OpsAlerter) rewriteQueue() error {
+	if o.queue == nil {
+		return nil
+	}
+	if err := o.queue.Truncate(0); err != nil {
+		return fmt.Errorf("failed to truncate ops alert queue: %w", err)
+	}
+	for _, alert := range o.pending {
+		if err := o.appendQueue(alert); err != nil {
+			return err
+		}
+	}
+	return o.queue.Sync()
+}
+
+// Close posts any queued alerts and closes the queue file. Alerts that can't
+// be posted stay in the file for the next process.
+func (o *OpsAlerter) Close() error {
+	o.mu.Lock()
+	if o.flushTimer != nil {
+		o.flushTimer.Stop()
+		o.flushTimer = nil
+	}
+	o.mu.Unlock()
+
+	err := o.postDigest(context.Background())
+	if err != nil {
+		log.Printf("Failed to post ops alert digest on shutdown, keeping it queued: %v", err)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.queue != nil {
+		if cerr := o.queue.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+		o.queue = nil
+	}
+	return err
+}
+
+// chatMessage is the incoming-webhook payload. Attachments render as a
+// coloured card with fields in Slack and Mattermost.
+type chatMessage struct {
+	Text        string           `json:"text"`
+	Attachments []chatAttachment `json:"attachments,omitempty"`
+}
+
+type chatAttachment struct {
+	Color  string      `json:"color,omitempty"`
+	Fields []chatField `json:"fields"`
+}
+
+type chatField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+func (o *OpsAlerter) alertMessage(alert shared.OpsAlert) chatMessage {
+	fields := []chatField{
+		{Title: "Merchant", Value: fmt.Sprintf("%s (%s)", alert.MerchantName, alert.MerchantID), Short: true},
+		{Title: "Country", Value: alert.Country, Short: true},
+		{Title: "Outcome", Value: string(alert.Outcome), Short: true},
+		{Title: "Reason", Value: alert.Reason},
+	}
+	if link := o.workflowLink(alert); link != "" {
+		fields = append(fields, chatField{Title: "Workflow", Value: link})
+	}
+	return chatMessage{
+		Text:        fmt.Sprintf(":rotating_light: Onboarding %s for merchant %s", alert.Outcome, alert.MerchantID),
+		Attachments: []chatAttachment{{Color: "danger", Fields: fields}},
+	}
+}
+
+func (o *OpsAlerter) digestMessage(alerts []shared.OpsAlert) chatMessage {
+	byOutcome := make(map[shared.OnboardingStatus][]string)
+	for _, alert := range alerts {
+		byOutcome[alert.Outcome] = append(byOutcome[alert.Outcome], alert.MerchantID)
+	}
+	outcomes := make([]string, 0, len(byOutcome))
+	for outcome := range byOutcome {
+		outcomes = append(outcomes, string(outcome))
+	}
+	sort.Strings(outcomes)
+
+	var fields []chatField
+	for _, outcome := range outcomes {
+		merchants := byOutcome[shared.OnboardingStatus(outcome)]
+		fields = append(fields, chatField{
+			Title: fmt.Sprintf("%s (%d)", outcome, len(merchants)),
+			Value: strings.Join(merchants, ", "),
+		})
+	}
+	return chatMessage{
+		Text:        fmt.Sprintf(":rotating_light: %d more onboarding alerts in the last %s", len(alerts), o.Window),
+		Attachments: []chatAttachment{{Color: "warning", Fields: fields}},
+	}
+}
+
+func (o *OpsAlerter) workflowLink(alert shared.OpsAlert) string {
+	if o.UIBaseURL == "" || alert.WorkflowID == "" {
+		return ""
+	}
+	return fmt.Sprintf("%s/namespaces/%s/workflows/%s/%s/history",
+		strings.TrimRight(o.UIBaseURL, "/"),
+		url.PathEscape(alert.Namespace),
+		url.PathEscape(alert.WorkflowID),
+		url.PathEscape(alert.RunID),
+	)
+}
+
+func (o *OpsAlerter) post(ctx context.Context, msg chatMessage) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := o.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post ops alert: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("ops webhook returned %s", resp.Status)
+	}
+	return nil
+}
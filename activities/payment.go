@@ -2,20 +2,148 @@ package activities
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	"temporal-customer-onboarding/payments"
+	"temporal-customer-onboarding/shared"
 )
 
+// PaymentsAPI is the payment platform operations the activities depend on.
+// payments.Client implements it against the real platform.
+type PaymentsAPI interface {
+	DisablePayments(ctx context.Context, merchantID string) error
+	HoldPayouts(ctx context.Context, merchantID string) error
+	CapTransactionVolume(ctx context.Context, merchantID string, dailyCap int64) error
+	LiftRestrictions(ctx context.Context, merchantID string) error
+	ApplyConditions(ctx context.Context, merchantID string, conditions payments.Conditions) error
+}
+
 // DisablePayments disables payment processing for a merchant
 // who has not completed onboarding by the compliance deadline.
 // Idempotency: naturally idempotent — setting status to "disabled" twice has the same effect.
 func (a *Activities) DisablePayments(ctx context.Context, merchantID string) error {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Disabling payments for merchant", "merchantId", merchantID)
+	logger.Info("Disabling payments for merchant")
 
-	// In production: call payment services API to disable processing.
+	if a.Payments == nil {
+		// No payment platform configured (local development).
+		logger.Info("No payments API configured, skipping platform call")
+		return nil
+	}
+
+	if err := a.Payments.DisablePayments(ctx, merchantID); err != nil {
+		return paymentsError(err)
+	}
 	logger.Info(fmt.Sprintf("Payments disabled successfully for merchant %s", merchantID))
 
 	return nil
 }
+
+// HoldPayouts holds payouts for a merchant who is approaching the compliance
+// deadline. The merchant can still accept payments.
+// Idempotency: naturally idempotent — holding payouts twice has the same effect.
+func (a *Activities) HoldPayouts(ctx context.Context, merchantID string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Holding payouts for merchant")
+
+	if a.Payments == nil {
+		logger.Info("No payments API configured, skipping platform call")
+		return nil
+	}
+
+	if err := a.Payments.HoldPayouts(ctx, merchantID); err != nil {
+		return paymentsError(err)
+	}
+	logger.Info("Payouts held")
+
+	return nil
+}
+
+// CapTransactionVolume limits a merchant's daily processed volume as a
+// last step before payments are disabled.
+// Idempotency: naturally idempotent — setting the same cap twice has the same effect.
+func (a *Activities) CapTransactionVolume(ctx context.Context, req shared.VolumeCapRequest) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Capping transaction volume for merchant",
+		"dailyVolumeCap", req.DailyVolumeCap,
+	)
+
+	if a.Payments == nil {
+		logger.Info("No payments API configured, skipping platform call")
+		return nil
+	}
+
+	if err := a.Payments.CapTransactionVolume(ctx, req.MerchantID, req.DailyVolumeCap); err != nil {
+		return paymentsError(err)
+	}
+	logger.Info("Transaction volume capped")
+
+	return nil
+}
+
+// LiftRestrictions removes every onboarding restriction from a merchant
+// once KYC is approved.
+// Idempotency: naturally idempotent — lifting restrictions twice has the same effect.
+func (a *Activities) LiftRestrictions(ctx context.Context, merchantID string) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Lifting payment restrictions for merchant")
+
+	if a.Payments == nil {
+		logger.Info("No payments API configured, skipping platform call")
+		return nil
+	}
+
+	if err := a.Payments.LiftRestrictions(ctx, merchantID); err != nil {
+		return paymentsError(err)
+	}
+	logger.Info("Payment restrictions lifted")
+
+	return nil
+}
+
+// ApplyMerchantConditions puts the conditions of a conditional approval in
+// force on the payment platform.
+// Idempotency: naturally idempotent — conditions are replaced, not added to.
+func (a *Activities) ApplyMerchantConditions(ctx context.Context, req shared.MerchantConditionsRequest) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Applying approval conditions for merchant",
+		"monthlyVolumeLimit", req.Conditions.MonthlyVolumeLimit,
+		"noThirdPartyPayouts", req.Conditions.NoThirdPartyPayouts,
+		"enhancedMonitoring", req.Conditions.EnhancedMonitoring,
+	)
+
+	if a.Payments == nil {
+		logger.Info("No payments API configured, skipping platform call")
+		return nil
+	}
+
+	conditions := payments.Conditions{
+		MonthlyVolumeLimit:       req.Conditions.MonthlyVolumeLimit,
+		ThirdPartyPayoutsBlocked: req.Conditions.NoThirdPartyPayouts,
+		EnhancedMonitoring:       req.Conditions.EnhancedMonitoring,
+	}
+	if err := a.Payments.ApplyConditions(ctx, req.MerchantID, conditions); err != nil {
+		return paymentsError(err)
+	}
+	logger.Info("Approval conditions applied")
+
+	return nil
+}
+
+// paymentsError maps payment platform errors onto Temporal retry semantics:
+// an unknown merchant or a rejected request fails fast, while rate limiting,
+// server errors and network failures are left to the retry policy.
+func paymentsError(err error) error {
+	if errors.Is(err, payments.ErrMerchantNotFound) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), shared.ErrTypeMerchantNotFound, err)
+	}
+	var apiErr *payments.APIError
+	if errors.As(err, &apiErr) && !apiErr.Retryable() {
+		return temporal.NewNonRetryableApplicationError(err.Error(), shared.ErrTypePaymentsRequestRejected, err)
+	}
+	return err
+}
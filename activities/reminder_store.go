@@ -0,0 +1,129 @@
+package activities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// SentReminderStore records which reminders have already been delivered,
+// keyed by the reminder's idempotency key. SendReminder consults it before
+// sending so that activity retries and workflow resets never deliver the
+// same reminder twice.
+type SentReminderStore interface {
+	// Lookup returns the reminder ID recorded for key, if any.
+	Lookup(key string) (reminderID string, found bool, err error)
+	// Record marks key as sent with the given reminder ID.
+	Record(key, reminderID string) error
+}
+
+// sentReminderEntry is one line in the FileSentReminderStore file.
+type sentReminderEntry struct {
+	Key        string `json:"key"`
+	ReminderID string `json:"reminderId"`
+}
+
+// FileSentReminderStore is a durable SentReminderStore backed by an
+// append-only JSON Lines file. Every Record is fsynced before returning, so a
+// key that was recorded survives a worker crash or restart.
+type FileSentReminderStore struct {
+	mu   sync.Mutex
+	file *os.File
+	sent map[string]string
+}
+
+// OpenFileSentReminderStore opens (or creates) the store at path and loads
+// all previously recorded keys into memory.
+func OpenFileSentReminderStore(path string) (*FileSentReminderStore, error) {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create reminder store directory: %w", err)
+		}
+	}
+
+	s := &FileSentReminderStore{sent: make(map[string]string)}
+	if err := s.load(path); err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reminder store: %w", err)
+	}
+	s.file = f
+	return s, nil
+}
+
+// load reads the recorded keys. A final line without a newline was torn by
+// a crash mid-write: its reminder was never confirmed as recorded, and it is
+// truncated so the next append starts on a fresh line.
+func (s *FileSentReminderStore) load(path string) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read reminder store: %w", err)
+	}
+
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	for _, line := range bytes.Split(data[:complete], []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var entry sentReminderEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		s.sent[entry.Key] = entry.ReminderID
+	}
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return fmt.Errorf("failed to truncate torn reminder store entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Lookup implements SentReminderStore.
+func (s *FileSentReminderStore) Lookup(key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id, ok := s.sent[key]
+	return id, ok, nil
+}
+
+// Record implements SentReminderStore.
+func (s *FileSentReminderStore) Record(key, reminderID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sent[key]; ok {
+		return nil
+	}
+
+	line, err := json.Marshal(sentReminderEntry{Key: key, ReminderID: reminderID})
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write reminder store: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync reminder store: %w", err)
+	}
+	s.sent[key] = reminderID
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *FileSentReminderStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,76 @@
+package activities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go.temporal.io/sdk/activity"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// StatusPublisher delivers workflow status events to subscribers, such as
+// the API server's server-sent events stream.
+type StatusPublisher interface {
+	PublishStatus(ctx context.Context, event shared.StatusEvent) error
+}
+
+// PublishStatusEvent publishes a change in the onboarding's status or
+// payment restriction.
+// Idempotency: safe to retry — subscribers drop events whose sequence they
+// have already seen.
+func (a *Activities) PublishStatusEvent(ctx context.Context, event shared.StatusEvent) error {
+	logger := activity.GetLogger(ctx)
+	logger.Info("Publishing status event",
+		"sequence", event.Sequence,
+		"status", event.Status,
+		"restriction", event.Restriction,
+	)
+
+	if a.StatusEvents == nil {
+		// Nobody to publish to (local development).
+		return nil
+	}
+	return a.StatusEvents.PublishStatus(ctx, event)
+}
+
+// HTTPStatusPublisher posts status events to the API server's internal
+// status-events endpoint, which fans them out to stream subscribers.
+type HTTPStatusPublisher struct {
+	URL        string
+	Token      string // Bearer token; the API server's STATUS_EVENTS_TOKEN.
+	HTTPClient *http.Client
+}
+
+// PublishStatus implements StatusPublisher.
+func (p *HTTPStatusPublisher) PublishStatus(ctx context.Context, event shared.StatusEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to publish status event: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("status events endpoint returned %s", resp.Status)
+	}
+	return nil
+}
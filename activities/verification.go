@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strings"
+	"time"
 	"unicode"
 
 	"go.temporal.io/sdk/activity"
@@ -12,27 +14,38 @@ import (
 	"temporal-customer-onboarding/shared"
 )
 
+// lowConfidenceSuffix marks a demo document ID that the supplier matches
+// with low confidence.
+const lowConfidenceSuffix = "-LC"
+
 // ValidateWithSupplier sends the merchant's identity document to a
 // third-party verification supplier (e.g., Onfido, Jumio) and returns the result.
 // Idempotency: naturally idempotent — validation is a read operation with no side effects.
 func (a *Activities) ValidateWithSupplier(ctx context.Context, doc shared.DocumentUpload) (shared.VerificationResult, error) {
 	logger := activity.GetLogger(ctx)
 	logger.Info("Sending document to verification supplier",
-		"merchantId", doc.MerchantID,
 		"documentType", doc.DocumentType,
 		"documentId", doc.DocumentID,
 	)
+	start := time.Now()
+
+	// DEMO: a numeric document ID with the "-LC" suffix is a low-confidence
+	// match, which compliance accepts only with conditions.
+	digits, lowConfidence := doc.DocumentID, false
+	if d, ok := strings.CutSuffix(doc.DocumentID, lowConfidenceSuffix); ok && d != "" {
+		digits, lowConfidence = d, true
+	}
 
 	// Validate: document ID must contain only digits.
 	// In production, the supplier would perform this validation.
 	// Validate: document ID must contain only digits.
 	// In production, the supplier would perform this validation.
-	for _, ch := range doc.DocumentID {
+	for _, ch := range digits {
 		if !unicode.IsDigit(ch) {
 			logger.Info("Supplier rejected identity document — contains non-numeric characters",
-				"merchantId", doc.MerchantID,
 				"documentId", doc.DocumentID,
 			)
+			recordSupplierCall(ctx, start, "rejected")
 			return shared.VerificationResult{
 					Passed:         false,
 					VerificationID: fmt.Sprintf("SUP-FAIL-%s", doc.MerchantID),
@@ -54,15 +67,31 @@ func (a *Activities) ValidateWithSupplier(ctx context.Context, doc shared.Docume
 	// 75% chance of failure to demonstrate automatic activity retries.
 	// -------------------------------------------------------------------------
 	if rand.Float64() < 0.75 {
-		logger.Error("Simulating 3rd party API downtime (75% chance)",
-			"merchantId", doc.MerchantID,
-		)
+		logger.Error("Simulating 3rd party API downtime (75% chance)")
+		recordSupplierCall(ctx, start, "error")
 		return shared.VerificationResult{}, fmt.Errorf("simulated 3rd party API failure (transient)")
 	}
 
 	verificationID := fmt.Sprintf("SUP-%s", doc.MerchantID)
 	logger.Info("Supplier verified document successfully", "verificationId", verificationID)
 
+	if lowConfidence {
+		logger.Info("Supplier reported a low-confidence match")
+		recordSupplierCall(ctx, start, "conditional")
+		return shared.VerificationResult{
+			Passed:         true,
+			VerificationID: verificationID,
+			Details:        "Identity document verified by supplier (low-confidence match)",
+			Conditions: &shared.MerchantConditions{
+				MonthlyVolumeLimit:  5_000_000,
+				NoThirdPartyPayouts: true,
+				EnhancedMonitoring:  true,
+				Reason:              "low-confidence identity document match",
+			},
+		}, nil
+	}
+
+	recordSupplierCall(ctx, start, "passed")
 	return shared.VerificationResult{
 		Passed:         true,
 		VerificationID: verificationID,
@@ -76,7 +105,7 @@ func (a *Activities) ValidateWithSupplier(ctx context.Context, doc shared.Docume
 // Idempotency: naturally idempotent — validation is a read operation with no side effects.
 func (a *Activities) PerformInternalVerifications(ctx context.Context, merchantID string) (shared.VerificationResult, error) {
 	logger := activity.GetLogger(ctx)
-	logger.Info("Performing internal identity verification", "merchantId", merchantID)
+	logger.Info("Performing internal identity verification")
 
 	// In production: verify that the supplier-verified identity matches merchant records.
 	verificationID := fmt.Sprintf("INT-%s", merchantID)
@@ -0,0 +1,197 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// keepAliveInterval is how often an idle event stream sends a comment so
+// proxies don't close it.
+const keepAliveInterval = 15 * time.Second
+
+// Bus fans status events out to the streams following each merchant. It
+// remembers each merchant's latest event so a new stream starts from the
+// current state, and drops events older than that.
+//
+// A Bus lives in one API server process: events posted to one replica only
+// reach streams on that replica. Run a single API server, or route the
+// activity worker's posts and each merchant's streams to the same replica.
+type Bus struct {
+	mu     sync.Mutex
+	latest map[string]shared.StatusEvent // merchant ID → latest event
+	subs   map[string]map[chan shared.StatusEvent]struct{}
+}
+
+// NewBus returns an empty Bus.
+func NewBus() *Bus {
+	return &Bus{
+		latest: make(map[string]shared.StatusEvent),
+		subs:   make(map[string]map[chan shared.StatusEvent]struct{}),
+	}
+}
+
+// Publish delivers e to the merchant's subscribers. It returns false if e is
+// stale: a repeat or an older event from the same run. A subscriber too slow
+// to keep up is disconnected rather than allowed to block the bus.
+func (b *Bus) Publish(e shared.StatusEvent) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if prev, ok := b.latest[e.MerchantID]; ok && prev.RunID == e.RunID && e.Sequence <= prev.Sequence {
+		return false
+	}
+	// Finished onboardings are answered from the status query, so there's
+	// no need to keep their last event around.
+	if e.Status.Final() {
+		delete(b.latest, e.MerchantID)
+	} else {
+		b.latest[e.MerchantID] = e
+	}
+
+	for ch := range b.subs[e.MerchantID] {
+		select {
+		case ch <- e:
+		default:
+			delete(b.subs[e.MerchantID], ch)
+			close(ch)
+		}
+	}
+	return true
+}
+
+// Subscribe follows the merchant's events. It returns the latest event, if
+// the bus has one, and a channel of later events that is closed if the
+// subscriber falls behind. Call cancel when done.
+func (b *Bus) Subscribe(merchantID string) (latest *shared.StatusEvent, events <-chan shared.StatusEvent, cancel func()) {
+	ch := make(chan shared.StatusEvent, 16)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.subs[merchantID] == nil {
+		b.subs[merchantID] = make(map[chan shared.StatusEvent]struct{})
+	}
+	b.subs[merchantID][ch] = struct{}{}
+	if e, ok := b.latest[merchantID]; ok {
+		latest = &e
+	}
+
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[merchantID][ch]; ok {
+			delete(b.subs[merchantID], ch)
+			close(ch)
+		}
+		if len(b.subs[merchantID]) == 0 {
+			delete(b.subs, merchantID)
+		}
+	}
+	return latest, ch, cancel
+}
+
+// publishEvent receives status events from the PublishStatusEvent activity.
+func (s *Server) publishEvent(rw http.ResponseWriter, r *http.Request) {
+	var e shared.StatusEvent
+	if err := decodeBody(rw, r, &e); err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if e.MerchantID == "" || e.Status == "" {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, "merchantId and status are required")
+		return
+	}
+	s.Events.Publish(e)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// streamEvents streams the merchant's status changes as server-sent events,
+// starting with the current state. The stream ends after the final status.
+// A client reconnecting with Last-Event-ID skips events it has already seen.
+func (s *Server) streamEvents(rw http.ResponseWriter, r *http.Request) {
+	merchantID := r.PathValue("merchantId")
+	flusher, ok := rw.(http.Flusher)
+	if !ok {
+		writeError(rw, http.StatusInternalServerError, CodeInternal, "streaming not supported")
+		return
+	}
+
+	// Subscribe before reading the current state so no change slips
+	// between the two.
+	latest, events, cancel := s.Events.Subscribe(merchantID)
+	defer cancel()
+
+	current := latest
+	if current == nil {
+		// Nothing published since this server started: ask the workflow.
+		status, err := s.Service.Status(r.Context(), merchantID)
+		if err != nil {
+			writeServiceError(rw, err)
+			return
+		}
+		current = &shared.StatusEvent{
+			MerchantID:  merchantID,
+			WorkflowID:  shared.OnboardingWorkflowID(merchantID),
+			RunID:       status.RunID,
+			Sequence:    status.Sequence,
+			Status:      status.Status,
+			Restriction: status.Restriction,
+			Deadline:    status.Deadline,
+			At:          s.now(),
+		}
+	}
+
+	rw.Header().Set("Content-Type", "text/event-stream")
+	rw.Header().Set("Cache-Control", "no-cache")
+	rw.Header().Set("X-Accel-Buffering", "no") // Don't let nginx buffer the stream.
+	rw.WriteHeader(http.StatusOK)
+
+	lastSeen, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	if current.Sequence == 0 || current.Sequence > lastSeen {
+		writeEvent(rw, *current)
+		lastSeen = current.Sequence
+	}
+	flusher.Flush()
+	if current.Status.Final() {
+		return
+	}
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(rw, ": keepalive\n\n")
+			flusher.Flush()
+		case e, ok := <-events:
+			if !ok {
+				return // Fell behind; the client reconnects with Last-Event-ID.
+			}
+			if e.RunID == current.RunID && e.Sequence <= lastSeen {
+				continue
+			}
+			current, lastSeen = &e, e.Sequence
+			writeEvent(rw, e)
+			flusher.Flush()
+			if e.Status.Final() {
+				return
+			}
+		}
+	}
+}
+
+// writeEvent writes e as a server-sent event of type "status".
+func writeEvent(rw http.ResponseWriter, e shared.StatusEvent) {
+	data, _ := json.Marshal(e)
+	if e.Sequence > 0 {
+		fmt.Fprintf(rw, "id: %d\n", e.Sequence)
+	}
+	fmt.Fprintf(rw, "event: status\ndata: %s\n\n", data)
+}
@@ -0,0 +1,302 @@
+// Package api is the HTTP/JSON API the merchant portal uses to start
+// onboarding, submit documents and follow progress. Every endpoint except
+// the OpenAPI description requires a bearer token; errors share one body
+// shape, {"error":{"code":"...","message":"..."}}.
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+// OpenAPI is the OpenAPI 3 description of the API, served at /openapi.yaml.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+// maxBodyBytes caps request bodies.
+const maxBodyBytes = 1 << 20
+
+// Error codes returned in error bodies.
+const (
+	CodeInvalidRequest      = "invalid_request"
+	CodeUnauthorized        = "unauthorized"
+	CodeNotFound            = "not_found"
+	CodeAlreadyStarted      = "already_started"
+	CodeOnboardingRunning   = "onboarding_running"
+	CodeOnboardingCancelled = "onboarding_cancelled"
+	CodeInternal            = "internal"
+)
+
+// Service is the onboarding operations the API exposes.
+// *onboarding.Service implements it.
+type Service interface {
+	Start(ctx context.Context, req shared.OnboardingRequest) (onboarding.Execution, error)
+	SubmitDocument(ctx context.Context, merchantID, documentID string) error
+	Status(ctx context.Context, merchantID string) (shared.OnboardingStatusResponse, error)
+	Result(ctx context.Context, merchantID string, wait bool) (shared.OnboardingResult, error)
+	List(ctx context.Context, filter onboarding.ListFilter) ([]onboarding.Summary, error)
+}
+
+// Server serves the API. Create it with NewServer.
+type Server struct {
+	Service Service
+	// Token is the bearer token clients must present.
+	Token string
+	// StatusEventsToken is the bearer token the activity worker presents to
+	// POST /internal/status-events. It is separate from Token so portal
+	// clients can't publish status events; when empty, the endpoint rejects
+	// every request.
+	StatusEventsToken string
+	// Events carries status events from the workflows to event streams.
+	Events *Bus
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+
+	mux *http.ServeMux
+}
+
+// NewServer returns a Server over svc that accepts the given bearer token.
+func NewServer(svc Service, token string) *Server {
+	s := &Server{Service: svc, Token: token, Events: NewBus()}
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("GET /openapi.yaml", s.openAPI)
+	s.mux.Handle("POST /v1/onboardings", s.authenticated(s.start))
+	s.mux.Handle("GET /v1/onboardings", s.authenticated(s.list))
+	s.mux.Handle("POST /v1/onboardings/{merchantId}/documents", s.authenticated(s.submitDocument))
+	s.mux.Handle("GET /v1/onboardings/{merchantId}", s.authenticated(s.status))
+	s.mux.Handle("GET /v1/onboardings/{merchantId}/result", s.authenticated(s.result))
+	s.mux.Handle("GET /v1/onboardings/{merchantId}/events", s.authenticated(s.streamEvents))
+	s.mux.Handle("POST /internal/status-events", s.internal(s.publishEvent))
+	s.mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		writeError(rw, http.StatusNotFound, CodeNotFound, "no such endpoint")
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(rw, r)
+}
+
+// authenticated rejects requests without the server's bearer token.
+func (s *Server) authenticated(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if checkBearer(rw, r, s.Token) {
+			next(rw, r)
+		}
+	})
+}
+
+// internal rejects requests without the status-events token.
+func (s *Server) internal(next http.HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if checkBearer(rw, r, s.StatusEventsToken) {
+			next(rw, r)
+		}
+	})
+}
+
+// checkBearer reports whether r carries the bearer token want, responding
+// 401 if it doesn't. An empty want rejects every request.
+func checkBearer(rw http.ResponseWriter, r *http.Request, want string) bool {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || want == "" || subtle.ConstantTimeCompare([]byte(token), []byte(want)) != 1 {
+		rw.Header().Set("WWW-Authenticate", `Bearer realm="onboarding"`)
+		writeError(rw, http.StatusUnauthorized, CodeUnauthorized, "missing or invalid bearer token")
+		return false
+	}
+	return true
+}
+
+func (s *Server) openAPI(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/yaml")
+	_, _ = rw.Write(OpenAPI)
+}
+
+func (s *Server) start(rw http.ResponseWriter, r *http.Request) {
+	var req shared.OnboardingRequest
+	if err := decodeBody(rw, r, &req); err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if err := req.Validate(s.now()); err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+
+	exec, err := s.Service.Start(r.Context(), req)
+	if err != nil {
+		writeServiceError(rw, err)
+		return
+	}
+	rw.Header().Set("Location", "/v1/onboardings/"+req.Merchant.MerchantID)
+	writeJSON(rw, http.StatusCreated, exec)
+}
+
+// documentRequest is the body of POST /v1/onboardings/{merchantId}/documents.
+type documentRequest struct {
+	DocumentID string `json:"documentId"`
+}
+
+func (s *Server) submitDocument(rw http.ResponseWriter, r *http.Request) {
+	var body documentRequest
+	if err := decodeBody(rw, r, &body); err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+		return
+	}
+	if strings.TrimSpace(body.DocumentID) == "" {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, "documentId is required")
+		return
+	}
+
+	merchantID := r.PathValue("merchantId")
+	if err := s.Service.SubmitDocument(r.Context(), merchantID, body.DocumentID); err != nil {
+		writeServiceError(rw, err)
+		return
+	}
+	// The workflow verifies the document asynchronously; follow the status
+	// or its event stream.
+	writeJSON(rw, http.StatusAccepted, map[string]string{"merchantId": merchantID, "documentId": body.DocumentID})
+}
+
+func (s *Server) status(rw http.ResponseWriter, r *http.Request) {
+	status, err := s.Service.Status(r.Context(), r.PathValue("merchantId"))
+	if err != nil {
+		writeServiceError(rw, err)
+		return
+	}
+	writeJSON(rw, http.StatusOK, status)
+}
+
+func (s *Server) result(rw http.ResponseWriter, r *http.Request) {
+	result, err := s.Service.Result(r.Context(), r.PathValue("merchantId"), false)
+	if err != nil {
+		writeServiceError(rw, err)
+		return
+	}
+	// Always return the structured form, even for executions that started
+	// before the typed result and still encode as the legacy string.
+	result.Legacy = false
+	writeJSON(rw, http.StatusOK, result)
+}
+
+// list returns onboardings matching the query parameters onboardingStatus,
+// country, businessType, daysLeft, status (execution status) and limit.
+func (s *Server) list(rw http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	filter := onboarding.ListFilter{
+		Status:           q.Get("status"),
+		OnboardingStatus: q.Get("onboardingStatus"),
+		Country:          q.Get("country"),
+		BusinessType:     q.Get("businessType"),
+	}
+	limit, err := intParam(q.Get("limit"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, "limit "+err.Error())
+		return
+	}
+	daysLeft, err := intParam(q.Get("daysLeft"))
+	if err != nil {
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, "daysLeft "+err.Error())
+		return
+	}
+	filter.Limit = limit
+	filter.DeadlineWithin = time.Duration(daysLeft) * 24 * time.Hour
+
+	summaries, err := s.Service.List(r.Context(), filter)
+	if err != nil {
+		writeServiceError(rw, err)
+		return
+	}
+	if summaries == nil {
+		summaries = []onboarding.Summary{}
+	}
+	writeJSON(rw, http.StatusOK, map[string]interface{}{"onboardings": summaries})
+}
+
+// intParam parses an optional non-negative integer query parameter.
+func intParam(v string) (int, error) {
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, errors.New("must be a non-negative integer")
+	}
+	return n, nil
+}
+
+func (s *Server) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+// decodeBody decodes a JSON request body into v, rejecting unknown fields
+// and trailing data.
+func decodeBody(rw http.ResponseWriter, r *http.Request, v interface{}) error {
+	dec := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if dec.More() {
+		return errors.New("invalid JSON body: unexpected data after the object")
+	}
+	return nil
+}
+
+// errorBody is the body of every error response.
+type errorBody struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeError(rw http.ResponseWriter, status int, code, message string) {
+	var body errorBody
+	body.Error.Code = code
+	body.Error.Message = message
+	writeJSON(rw, status, body)
+}
+
+// writeServiceError maps a Service error to its HTTP status and error code.
+func writeServiceError(rw http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, onboarding.ErrInvalidRequest):
+		writeError(rw, http.StatusBadRequest, CodeInvalidRequest, err.Error())
+	case errors.Is(err, onboarding.ErrNotFound):
+		writeError(rw, http.StatusNotFound, CodeNotFound, "no onboarding for this merchant")
+	case errors.Is(err, onboarding.ErrAlreadyStarted):
+		writeError(rw, http.StatusConflict, CodeAlreadyStarted, "the merchant already has an onboarding")
+	case errors.Is(err, onboarding.ErrNotCompleted):
+		writeError(rw, http.StatusConflict, CodeOnboardingRunning, "the onboarding has not finished yet")
+	case errors.Is(err, onboarding.ErrCancelled):
+		writeError(rw, http.StatusConflict, CodeOnboardingCancelled, "the onboarding was cancelled")
+	default:
+		// Don't leak Temporal internals to the portal.
+		log.Printf("API request failed: %v", err)
+		writeError(rw, http.StatusInternalServerError, CodeInternal, "internal error")
+	}
+}
+
+func writeJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(v)
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"go.temporal.io/sdk/client"
+
+	"temporal-customer-onboarding/api"
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/tracing"
+)
+
+func main() {
+	token := os.Getenv("API_TOKEN")
+	if token == "" {
+		log.Fatal("API_TOKEN must be set to the bearer token the merchant portal presents")
+	}
+	// The activity worker presents this token to publish status events. It
+	// must differ from API_TOKEN so the portal can't forge them.
+	statusEventsToken := os.Getenv("STATUS_EVENTS_TOKEN")
+	if statusEventsToken == "" {
+		log.Print("STATUS_EVENTS_TOKEN is not set; status event streams only show the queried status")
+	} else if statusEventsToken == token {
+		log.Fatal("STATUS_EVENTS_TOKEN must differ from API_TOKEN")
+	}
+	addr := os.Getenv("API_ADDR")
+	if addr == "" {
+		addr = ":8080"
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	// Each API request is a server span, continuing the portal's trace if it
+	// sends one, and the onboardings it starts or signals join it.
+	tracer, err := tracing.Setup(context.Background(), cfg.Tracing, "onboarding-api")
+	if err != nil {
+		log.Fatalf("Unable to set up tracing: %v", err)
+	}
+	defer tracer.Shutdown(context.Background())
+	c, err := cfg.Dial(func(opts *client.Options) {
+		opts.Interceptors = append(opts.Interceptors, tracer.Interceptors...)
+	})
+	if err != nil {
+		log.Fatalf("Unable to create Temporal client: %v", err)
+	}
+	defer c.Close()
+	svc := onboarding.NewService(c)
+	svc.TaskQueue = cfg.TaskQueues.Workflow
+	svc.ActivityTaskQueue = cfg.TaskQueues.Activity
+
+	server := api.NewServer(svc, token)
+	server.StatusEventsToken = statusEventsToken
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           tracing.Handler(server, "onboarding-api"),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("Serving the onboarding API on %s (spec at /openapi.yaml)", addr)
+	if err := srv.ListenAndServe(); err != nil {
+		log.Fatalf("API server stopped: %v", err)
+	}
+}
@@ -0,0 +1,214 @@
+// Package batch applies one operation — a deadline extension, an extra
+// reminder or a cancellation — to every running onboarding matched by a
+// visibility query. Progress is recorded in a checkpoint file so an
+// interrupted batch can be resumed without repeating work.
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workpool"
+)
+
+// Service is the onboarding operations a batch uses.
+// *onboarding.Service implements it.
+type Service interface {
+	List(ctx context.Context, filter onboarding.ListFilter) ([]onboarding.Summary, error)
+	ExtendDeadline(ctx context.Context, merchantID string, ext shared.DeadlineExtension) error
+	ResendReminder(ctx context.Context, merchantID string, resend shared.ReminderResend) error
+	Cancel(ctx context.Context, merchantID string) error
+}
+
+// Action is what a batch does to each onboarding.
+type Action string
+
+const (
+	ActionExtend Action = "extend" // Extend the deadline by Operation.Days.
+	ActionRemind Action = "remind" // Send an extra reminder.
+	ActionCancel Action = "cancel" // Cancel the onboarding.
+)
+
+// Operation describes a batch. Its ID doubles as the request ID of the
+// signals it sends, so an onboarding signalled twice — say, because the
+// batch was interrupted before checkpointing it — applies the operation once.
+type Operation struct {
+	ID     string                `json:"id"`
+	Action Action                `json:"action"`
+	Filter onboarding.ListFilter `json:"filter"`
+	Days   int                   `json:"days,omitempty"`   // ActionExtend only.
+	Reason string                `json:"reason,omitempty"` // Recorded in the workflow log.
+}
+
+// Validate checks the operation before it runs.
+func (op Operation) Validate() error {
+	switch op.Action {
+	case ActionExtend:
+		if op.Days <= 0 {
+			return errors.New("extend needs a positive number of days")
+		}
+	case ActionRemind, ActionCancel:
+	default:
+		return fmt.Errorf("unknown action %q", op.Action)
+	}
+	if op.ID == "" {
+		return errors.New("batch ID is required")
+	}
+	return nil
+}
+
+// Status is the outcome of a batch for one onboarding.
+type Status string
+
+const (
+	StatusApplied  Status = "applied"
+	StatusDone     Status = "done"     // Applied by an earlier run, per the checkpoint.
+	StatusFinished Status = "finished" // The onboarding ended before the batch reached it.
+	StatusFailed   Status = "failed"   // Retried when the batch is resumed.
+	StatusPlanned  Status = "planned"  // Dry run: the operation would be applied.
+)
+
+// Result is the outcome for one onboarding.
+type Result struct {
+	MerchantID string `json:"merchantId"`
+	WorkflowID string `json:"workflowId"`
+	Status     Status `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Runner runs batches.
+type Runner struct {
+	Service Service
+	// Concurrency is the number of operations in flight at once; 0 means 4.
+	Concurrency int
+	// Rate is the maximum number of operations per second; 0 means unlimited.
+	Rate float64
+	// MaxTargets refuses batches matching more onboardings than this, as a
+	// guard against an overly broad query; 0 means 10000.
+	MaxTargets int
+	// DryRun lists the onboardings the batch would touch without changing
+	// anything or writing the checkpoint.
+	DryRun bool
+	// Checkpoint records progress; nil runs without one.
+	Checkpoint *Checkpoint
+	// Progress, if set, receives a line per onboarding as the batch runs.
+	Progress io.Writer
+}
+
+// Run applies op to every running onboarding matching op.Filter and
+// returns one Result per onboarding, in listing order. Onboardings the
+// checkpoint records as done are skipped.
+func (r *Runner) Run(ctx context.Context, op Operation) ([]Result, error) {
+	if err := op.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", onboarding.ErrInvalidRequest, err)
+	}
+	maxTargets := r.MaxTargets
+	if maxTargets <= 0 {
+		maxTargets = 10000
+	}
+	filter := op.Filter
+	filter.Status = "Running"
+	filter.Limit = maxTargets + 1
+	targets, err := r.Service.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("list onboardings: %w", err)
+	}
+	if len(targets) > maxTargets {
+		return nil, fmt.Errorf("%w: query matches more than %d onboardings; narrow it or raise the limit",
+			onboarding.ErrInvalidRequest, maxTargets)
+	}
+
+	// The pool calls Done one at a time, so report needs no lock.
+	var reported int
+	report := func(result Result) {
+		reported++
+		r.progress(reported, len(targets), result)
+	}
+
+	results := make([]Result, len(targets))
+	var pending []int
+	for i, t := range targets {
+		results[i] = Result{MerchantID: t.MerchantID, WorkflowID: t.WorkflowID}
+		switch {
+		case r.Checkpoint != nil && r.Checkpoint.Done(t.WorkflowID):
+			results[i].Status = StatusDone
+		case r.DryRun:
+			results[i].Status = StatusPlanned
+		default:
+			pending = append(pending, i)
+			continue
+		}
+		report(results[i])
+	}
+
+	pool := workpool.Pool{
+		Concurrency: r.Concurrency,
+		Rate:        r.Rate,
+		Done:        func(i int) { report(results[i]) },
+	}
+	pool.Run(ctx, pending, func(i int, err error) {
+		if err != nil {
+			results[i].Status = StatusFailed
+			results[i].Error = fmt.Sprintf("not applied: %v", err)
+			return
+		}
+		r.apply(ctx, op, &results[i])
+	})
+
+	return results, nil
+}
+
+func (r *Runner) apply(ctx context.Context, op Operation, result *Result) {
+	var err error
+	switch op.Action {
+	case ActionExtend:
+		err = r.Service.ExtendDeadline(ctx, result.MerchantID, shared.DeadlineExtension{Days: op.Days, Reason: op.Reason, RequestID: op.ID})
+	case ActionRemind:
+		err = r.Service.ResendReminder(ctx, result.MerchantID, shared.ReminderResend{Reason: op.Reason, RequestID: op.ID})
+	case ActionCancel:
+		err = r.Service.Cancel(ctx, result.MerchantID)
+	}
+	switch {
+	case errors.Is(err, onboarding.ErrNotFound):
+		result.Status = StatusFinished
+	case err != nil:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		return
+	default:
+		result.Status = StatusApplied
+	}
+
+	if r.Checkpoint != nil {
+		if err := r.Checkpoint.Record(result.WorkflowID, time.Now()); err != nil {
+			// The operation itself succeeded; a resumed batch will signal
+			// again and the workflow will ignore the repeat.
+			result.Error = fmt.Sprintf("checkpoint: %v", err)
+		}
+	}
+}
+
+func (r *Runner) progress(n, total int, result Result) {
+	if r.Progress == nil {
+		return
+	}
+	line := fmt.Sprintf("[%d/%d] %s %s", n, total, result.MerchantID, result.Status)
+	if result.Error != "" {
+		line += ": " + result.Error
+	}
+	fmt.Fprintln(r.Progress, line)
+}
+
+// Summary counts results by status.
+func Summary(results []Result) map[Status]int {
+	counts := make(map[Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
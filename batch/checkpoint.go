@@ -0,0 +1,157 @@
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Checkpoint is a batch's progress file. It is JSON Lines: the first line
+// records the Operation, each later line an onboarding the operation was
+// applied to. The file is created on the first Record, so a dry run or a
+// batch that matches nothing leaves no file behind.
+type Checkpoint struct {
+	// Operation is the batch the file belongs to. When resuming, it is read
+	// from the file, including the batch ID.
+	Operation Operation
+
+	path string
+	mu   sync.Mutex
+	f    *os.File
+	done map[string]bool // workflow ID → applied
+}
+
+// checkpointEntry is one line after the header.
+type checkpointEntry struct {
+	WorkflowID string    `json:"workflowId"`
+	At         time.Time `json:"at"`
+}
+
+// OpenCheckpoint opens the checkpoint at path for op. If the file exists,
+// the batch resumes: op must match the recorded operation apart from its
+// ID, and the recorded ID is used so repeated signals are recognised.
+//
+// A final line without a newline was torn by an interrupted write: the
+// onboarding on it is simply signalled again, and the line is truncated so
+// the next Record starts on a fresh line.
+func OpenCheckpoint(path string, op Operation) (*Checkpoint, error) {
+	c := &Checkpoint{Operation: op, path: path, done: make(map[string]bool)}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read checkpoint %s: %w", path, err)
+	}
+
+	complete := bytes.LastIndexByte(data, '\n') + 1
+	lines := bytes.Split(data[:complete], []byte("\n"))
+	if complete > 0 {
+		var recorded Operation
+		if err := json.Unmarshal(lines[0], &recorded); err != nil {
+			return nil, fmt.Errorf("checkpoint %s: invalid header: %w", path, err)
+		}
+		op.ID = recorded.ID
+		if !reflect.DeepEqual(op, recorded) {
+			return nil, fmt.Errorf("checkpoint %s belongs to a different batch (%s %s); use another file", path, recorded.Action, recorded.ID)
+		}
+		c.Operation = recorded
+
+		for i, line := range lines[1:] {
+			if len(line) == 0 {
+				continue
+			}
+			var e checkpointEntry
+			if err := json.Unmarshal(line, &e); err != nil {
+				return nil, fmt.Errorf("checkpoint %s: line %d: %w", path, i+2, err)
+			}
+			c.done[e.WorkflowID] = true
+		}
+	}
+	if complete < len(data) {
+		if err := os.Truncate(path, int64(complete)); err != nil {
+			return nil, fmt.Errorf("truncate torn checkpoint line %s: %w", path, err)
+		}
+	}
+	return c, nil
+}
+
+// Done reports whether the checkpoint records the operation as applied to
+// the workflow.
+func (c *Checkpoint) Done(workflowID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.done[workflowID]
+}
+
+// Len returns the number of onboardings recorded as done.
+func (c *Checkpoint) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.done)
+}
+
+// Record appends the workflow to the checkpoint.
+func (c *Checkpoint) Record(workflowID string, at time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.f == nil {
+		if err := c.open(); err != nil {
+			return err
+		}
+	}
+	line, err := json.Marshal(checkpointEntry{WorkflowID: workflowID, At: at.UTC()})
+	if err != nil {
+		return err
+	}
+	if _, err := c.f.Write(append(line, '\n')); err != nil {
+		return err
+	}
+	c.done[workflowID] = true
+	return nil
+}
+
+// open opens the file for appending, writing the header if it is new.
+func (c *Checkpoint) open() error {
+	f, err := os.OpenFile(c.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	if info.Size() == 0 {
+		header, err := json.Marshal(c.Operation)
+		if err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := f.Write(append(header, '\n')); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	c.f = f
+	return nil
+}
+
+// Close closes the checkpoint file.
+func (c *Checkpoint) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.f == nil {
+		return nil
+	}
+	err := c.f.Close()
+	c.f = nil
+	return err
+}
@@ -0,0 +1,137 @@
+package bulkimport
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workpool"
+)
+
+// Starter starts one onboarding. *onboarding.Service implements it.
+type Starter interface {
+	Start(ctx context.Context, req shared.OnboardingRequest) (onboarding.Execution, error)
+}
+
+// Status is the outcome of importing one row.
+type Status string
+
+const (
+	StatusStarted Status = "started"
+	StatusSkipped Status = "skipped" // The merchant already has an onboarding.
+	StatusInvalid Status = "invalid"
+	StatusFailed  Status = "failed"
+	StatusValid   Status = "valid" // Dry run: the row would be started.
+)
+
+// Result is the report line for one row.
+type Result struct {
+	Line       int    `json:"line"`
+	MerchantID string `json:"merchantId"`
+	Status     Status `json:"status"`
+	WorkflowID string `json:"workflowId,omitempty"`
+	RunID      string `json:"runId,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Importer starts onboardings for rows.
+type Importer struct {
+	Starter Starter
+	// Rate is the maximum number of starts per second; 0 means unlimited.
+	Rate float64
+	// Concurrency is the number of starts in flight at once; 0 means 4.
+	Concurrency int
+	// DryRun validates rows without starting anything.
+	DryRun bool
+	// Now returns the current time; defaults to time.Now.
+	Now func() time.Time
+}
+
+// Run imports rows and returns one Result per row, in input order. Rows that
+// fail validation, including repeats of an earlier merchant ID, are reported
+// as invalid and not started. If ctx is cancelled, rows not yet started are
+// reported as failed.
+func (im *Importer) Run(ctx context.Context, rows []Row) []Result {
+	now := time.Now
+	if im.Now != nil {
+		now = im.Now
+	}
+	results := make([]Result, len(rows))
+	seen := make(map[string]int) // merchant ID → line
+	var pending []int
+	for i, row := range rows {
+		results[i] = Result{Line: row.Line, MerchantID: row.Merchant.MerchantID}
+		err := Validate(row, now())
+		if err == nil {
+			if line, dup := seen[row.Merchant.MerchantID]; dup {
+				err = fmt.Errorf("duplicate merchantId, first seen on line %d", line)
+			}
+		}
+		if err != nil {
+			results[i].Status = StatusInvalid
+			results[i].Error = err.Error()
+			continue
+		}
+		seen[row.Merchant.MerchantID] = row.Line
+		if im.DryRun {
+			results[i].Status = StatusValid
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	pool := workpool.Pool{Concurrency: im.Concurrency, Rate: im.Rate}
+	pool.Run(ctx, pending, func(i int, err error) {
+		if err != nil {
+			results[i].Status = StatusFailed
+			results[i].Error = fmt.Sprintf("not started: %v", err)
+			return
+		}
+		im.start(ctx, rows[i], &results[i])
+	})
+
+	return results
+}
+
+func (im *Importer) start(ctx context.Context, row Row, result *Result) {
+	exec, err := im.Starter.Start(ctx, row.Request())
+	switch {
+	case errors.Is(err, onboarding.ErrAlreadyStarted):
+		result.Status = StatusSkipped
+		result.WorkflowID = shared.OnboardingWorkflowID(row.Merchant.MerchantID)
+		result.Error = "merchant already has an onboarding"
+	case err != nil:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	default:
+		result.Status = StatusStarted
+		result.WorkflowID = exec.WorkflowID
+		result.RunID = exec.RunID
+	}
+}
+
+// Summary counts results by status.
+func Summary(results []Result) map[Status]int {
+	counts := make(map[Status]int)
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	return counts
+}
+
+// WriteReport writes results as CSV.
+func WriteReport(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"line", "merchantId", "status", "workflowId", "runId", "error"})
+	for _, r := range results {
+		_ = cw.Write([]string{strconv.Itoa(r.Line), r.MerchantID, string(r.Status), r.WorkflowID, r.RunID, r.Error})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,174 @@
+// Package bulkimport starts onboarding for batches of merchants migrated
+// from another system. Rows are read from CSV or JSON Lines, validated, and
+// started with client-side rate limiting and bounded concurrency; every row
+// gets a line in the result report.
+package bulkimport
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// Row is one merchant to onboard.
+type Row struct {
+	Line int // 1-based line in the input; the header is line 1 in CSV.
+
+	Merchant       shared.MerchantInfo
+	FirstPaymentAt *time.Time
+	DocumentID     string
+
+	// Err is set when the row could not be parsed.
+	Err error
+}
+
+// Request returns the OnboardingRequest for the row.
+func (r Row) Request() shared.OnboardingRequest {
+	return shared.OnboardingRequest{
+		Merchant:       r.Merchant,
+		FirstPaymentAt: r.FirstPaymentAt,
+		DocumentID:     r.DocumentID,
+	}
+}
+
+// csvColumns maps CSV header names to the row field they fill.
+var csvColumns = map[string]func(r *Row, v string) error{
+	"merchantid":          func(r *Row, v string) error { r.Merchant.MerchantID = v; return nil },
+	"name":                func(r *Row, v string) error { r.Merchant.Name = v; return nil },
+	"email":               func(r *Row, v string) error { r.Merchant.Email = v; return nil },
+	"phone":               func(r *Row, v string) error { r.Merchant.Phone = v; return nil },
+	"country":             func(r *Row, v string) error { r.Merchant.Country = v; return nil },
+	"businesstype":        func(r *Row, v string) error { r.Merchant.BusinessType = v; return nil },
+	"risktier":            func(r *Row, v string) error { r.Merchant.RiskTier = v; return nil },
+	"timezone":            func(r *Row, v string) error { r.Merchant.Timezone = v; return nil },
+	"accountmanageremail": func(r *Row, v string) error { r.Merchant.AccountManagerEmail = v; return nil },
+	"documentid":          func(r *Row, v string) error { r.DocumentID = v; return nil },
+	"firstpaymentat": func(r *Row, v string) error {
+		t, err := parseTime(v)
+		r.FirstPaymentAt = t
+		return err
+	},
+}
+
+// ReadCSV reads rows from CSV with a header line naming the columns, e.g.
+// merchantId,name,email,country,firstPaymentAt,documentId. Column names
+// match MerchantInfo's JSON names, case-insensitively. Rows that fail to
+// parse are returned with Err set.
+func ReadCSV(r io.Reader) ([]Row, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read CSV header: %w", err)
+	}
+	setters := make([]func(*Row, string) error, len(header))
+	hasMerchantID := false
+	for i, name := range header {
+		key := strings.ToLower(strings.TrimSpace(name))
+		set, ok := csvColumns[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown CSV column %q", name)
+		}
+		setters[i] = set
+		hasMerchantID = hasMerchantID || key == "merchantid"
+	}
+	if !hasMerchantID {
+		return nil, errors.New("CSV has no merchantId column")
+	}
+
+	var rows []Row
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		row := Row{Line: line}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return rows, fmt.Errorf("read CSV: %w", err)
+			}
+			row.Err = err
+			rows = append(rows, row)
+			continue
+		}
+		if len(record) != len(header) {
+			row.Err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+		}
+		for i := 0; i < len(record) && i < len(setters) && row.Err == nil; i++ {
+			if err := setters[i](&row, strings.TrimSpace(record[i])); err != nil {
+				row.Err = fmt.Errorf("%s: %w", header[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+// jsonRow is the JSON Lines form of a Row: MerchantInfo's fields plus
+// firstPaymentAt and documentId.
+type jsonRow struct {
+	shared.MerchantInfo
+	FirstPaymentAt string `json:"firstPaymentAt,omitempty"`
+	DocumentID     string `json:"documentId,omitempty"`
+}
+
+// ReadJSONL reads one JSON object per line. Blank lines are skipped; lines
+// that fail to parse are returned with Err set.
+func ReadJSONL(r io.Reader) ([]Row, error) {
+	var rows []Row
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		row := Row{Line: line}
+		var jr jsonRow
+		if err := json.Unmarshal([]byte(text), &jr); err != nil {
+			row.Err = fmt.Errorf("invalid JSON: %w", err)
+			rows = append(rows, row)
+			continue
+		}
+		row.Merchant = jr.MerchantInfo
+		row.DocumentID = jr.DocumentID
+		if t, err := parseTime(jr.FirstPaymentAt); err != nil {
+			row.Err = fmt.Errorf("firstPaymentAt: %w", err)
+		} else {
+			row.FirstPaymentAt = t
+		}
+		rows = append(rows, row)
+	}
+	return rows, scanner.Err()
+}
+
+// parseTime accepts RFC 3339 timestamps and plain dates (midnight UTC).
+// An empty value is nil.
+func parseTime(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid time %q, want RFC 3339 or YYYY-MM-DD", v)
+}
+
+// Validate checks a row before it is started. now is the current time.
+func Validate(r Row, now time.Time) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	return r.Request().Validate(now)
+}
@@ -0,0 +1,180 @@
+// Package calendar decides when merchant-facing messages may be sent: inside
+// a local-time window, on a business day in the merchant's country.
+//
+// Everything here is a pure function of its inputs plus data compiled into
+// the binary (holiday calendar and tz database), so it is safe to call from
+// workflow code.
+package calendar
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	_ "time/tzdata" // Same tz rules on every worker, regardless of host.
+
+	"temporal-customer-onboarding/shared"
+)
+
+//go:embed holidays.json
+var holidaysJSON []byte
+
+// holidays maps ISO country code → set of "2006-01-02" public holiday dates.
+var holidays = mustLoadHolidays(holidaysJSON)
+
+// coveredYears maps ISO country code → set of years holidays.json lists
+// holidays for.
+var coveredYears = yearsOf(holidays)
+
+func mustLoadHolidays(data []byte) map[string]map[string]bool {
+	var raw map[string][]string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		panic(fmt.Sprintf("calendar: invalid embedded holidays.json: %v", err))
+	}
+	out := make(map[string]map[string]bool, len(raw))
+	for country, dates := range raw {
+		set := make(map[string]bool, len(dates))
+		for _, d := range dates {
+			set[d] = true
+		}
+		out[country] = set
+	}
+	return out
+}
+
+func yearsOf(holidays map[string]map[string]bool) map[string]map[int]bool {
+	out := make(map[string]map[int]bool, len(holidays))
+	for country, dates := range holidays {
+		years := make(map[int]bool)
+		for d := range dates {
+			if t, err := time.Parse("2006-01-02", d); err == nil {
+				years[t.Year()] = true
+			}
+		}
+		out[country] = years
+	}
+	return out
+}
+
+// countryTimezones maps ISO country codes to the timezone used for
+// merchants that don't set one explicitly. Countries spanning several
+// zones use the capital's.
+var countryTimezones = map[string]string{
+	"AT": "Europe/Vienna",
+	"BE": "Europe/Brussels",
+	"CH": "Europe/Zurich",
+	"DE": "Europe/Berlin",
+	"DK": "Europe/Copenhagen",
+	"ES": "Europe/Madrid",
+	"FI": "Europe/Helsinki",
+	"FR": "Europe/Paris",
+	"GB": "Europe/London",
+	"IE": "Europe/Dublin",
+	"IT": "Europe/Rome",
+	"LU": "Europe/Luxembourg",
+	"NL": "Europe/Amsterdam",
+	"NO": "Europe/Oslo",
+	"PL": "Europe/Warsaw",
+	"PT": "Europe/Lisbon",
+	"SE": "Europe/Stockholm",
+	"US": "America/New_York",
+}
+
+// MerchantLocation returns the merchant's timezone: the explicit
+// MerchantInfo.Timezone if set and valid, otherwise the zone derived from
+// the country, otherwise UTC.
+func MerchantLocation(m shared.MerchantInfo) *time.Location {
+	if m.Timezone != "" {
+		if loc, err := time.LoadLocation(m.Timezone); err == nil {
+			return loc
+		}
+	}
+	if name, ok := countryTimezones[strings.ToUpper(m.Country)]; ok {
+		if loc, err := time.LoadLocation(name); err == nil {
+			return loc
+		}
+	}
+	return time.UTC
+}
+
+// IsHoliday reports whether date (in its own location) is a public holiday
+// in country according to the embedded calendar.
+func IsHoliday(country string, date time.Time) bool {
+	return holidays[strings.ToUpper(country)][date.Format("2006-01-02")]
+}
+
+// CoversHolidays reports whether the embedded calendar has public holidays
+// for country in the year of date. Where it doesn't, only weekends are
+// treated as non-business days.
+func CoversHolidays(country string, date time.Time) bool {
+	return coveredYears[strings.ToUpper(country)][date.Year()]
+}
+
+// HolidayCountries returns the countries the embedded calendar has public
+// holidays for.
+func HolidayCountries() []string {
+	countries := make([]string, 0, len(coveredYears))
+	for country := range coveredYears {
+		countries = append(countries, country)
+	}
+	sort.Strings(countries)
+	return countries
+}
+
+// IsBusinessDay reports whether date is a weekday that is not a public
+// holiday in country.
+func IsBusinessDay(country string, date time.Time) bool {
+	switch date.Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	return !IsHoliday(country, date)
+}
+
+// NextSendTime returns the earliest time at or after t that falls inside
+// window on a business day in the merchant's country, evaluated in loc.
+func NextSendTime(t time.Time, loc *time.Location, window shared.SendWindow, country string) time.Time {
+	local := t.In(loc)
+	for i := 0; i < 366; i++ {
+		y, m, d := local.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		// Built from wall-clock hours so DST changes don't shift the window.
+		start := time.Date(y, m, d, window.StartHour, 0, 0, 0, loc)
+		end := time.Date(y, m, d, window.EndHour, 0, 0, 0, loc)
+
+		if IsBusinessDay(country, day) && local.Before(end) {
+			if local.Before(start) {
+				return start
+			}
+			return local
+		}
+		local = time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	}
+	return t // Unreachable with a sane calendar; never block a send.
+}
+
+// PreviousSendTime returns the latest send slot at or before t: t itself if
+// it falls inside window on a business day in the merchant's country,
+// otherwise the last minute of the latest such window that closed before t,
+// evaluated in loc. ok is false if there is no such slot.
+func PreviousSendTime(t time.Time, loc *time.Location, window shared.SendWindow, country string) (sendAt time.Time, ok bool) {
+	local := t.In(loc)
+	for i := 0; i < 366; i++ {
+		y, m, d := local.Date()
+		day := time.Date(y, m, d, 0, 0, 0, 0, loc)
+		start := time.Date(y, m, d, window.StartHour, 0, 0, 0, loc)
+		end := time.Date(y, m, d, window.EndHour, 0, 0, 0, loc)
+
+		if IsBusinessDay(country, day) && !local.Before(start) {
+			if local.Before(end) {
+				return local, true
+			}
+			// The window is half-open, so its last slot starts a minute before end.
+			return end.Add(-time.Minute), true
+		}
+		local = time.Date(y, m, d-1, 23, 59, 59, 0, loc)
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"temporal-customer-onboarding/batch"
+)
+
+func (c *CLI) batch(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("batch", out)
+	var op batch.Operation
+	var daysLeft int
+	fs.StringVar(&op.Filter.OnboardingStatus, "onboarding-status", "", "onboarding status, e.g. AWAITING_KYC_DOCUMENTS")
+	fs.StringVar(&op.Filter.Country, "country", "", "ISO country code")
+	fs.StringVar(&op.Filter.BusinessType, "business-type", "", "business type")
+	fs.IntVar(&daysLeft, "days-left", 0, "only onboardings with fewer than N days until the deadline")
+	fs.StringVar(&op.Filter.Where, "query", "", `extra visibility query clause, e.g. "KYCAttempts > 1"`)
+	fs.IntVar(&op.Days, "days", 0, "extend: days to add to the deadline")
+	fs.StringVar(&op.Reason, "reason", "", "reason, recorded in the workflow log")
+	checkpoint := fs.String("checkpoint", "", "record progress in this file; rerun with the same file to resume")
+	r := &batch.Runner{Service: c.Service}
+	fs.IntVar(&r.Concurrency, "concurrency", 4, "operations in flight at once")
+	fs.Float64Var(&r.Rate, "rate", 10, "maximum operations per second; 0 for unlimited")
+	fs.IntVar(&r.MaxTargets, "max", 10000, "refuse to run if more onboardings than this match")
+	fs.BoolVar(&r.DryRun, "dry-run", false, "list the onboardings the batch would touch without changing them")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	op.Action = batch.Action(pos[0])
+	switch {
+	case op.Action != batch.ActionExtend && op.Action != batch.ActionRemind && op.Action != batch.ActionCancel:
+		return fmt.Errorf("%w: unknown batch action %q, use extend, remind or cancel", errUsage, pos[0])
+	case op.Action == batch.ActionExtend && op.Days <= 0:
+		return fmt.Errorf("%w: extend needs a positive -days", errUsage)
+	case op.Action != batch.ActionExtend && op.Days != 0:
+		return fmt.Errorf("%w: -days only applies to extend", errUsage)
+	case daysLeft < 0:
+		return fmt.Errorf("%w: -days-left must not be negative", errUsage)
+	case r.Concurrency <= 0 || r.Rate < 0 || r.MaxTargets <= 0:
+		return fmt.Errorf("%w: -concurrency and -max must be positive and -rate not negative", errUsage)
+	}
+	op.Filter.DeadlineWithin = time.Duration(daysLeft) * 24 * time.Hour
+	op.ID = fmt.Sprintf("batch-%s-%s", op.Action, time.Now().UTC().Format("20060102T150405Z"))
+
+	if *checkpoint != "" {
+		cp, err := batch.OpenCheckpoint(*checkpoint, op)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		defer cp.Close()
+		if n := cp.Len(); n > 0 && out.format == "text" {
+			fmt.Fprintf(c.Stderr, "Resuming batch %s: %d onboardings already done\n", cp.Operation.ID, n)
+		}
+		op = cp.Operation
+		r.Checkpoint = cp
+	}
+	if out.format == "text" {
+		r.Progress = c.Stderr
+	}
+
+	results, err := r.Run(ctx, op)
+	if err != nil {
+		return err
+	}
+
+	summary := batch.Summary(results)
+	out.print(struct {
+		BatchID string               `json:"batchId"`
+		Summary map[batch.Status]int `json:"summary"`
+		Results []batch.Result       `json:"results"`
+	}{op.ID, summary, results}, func(w io.Writer) {
+		fmt.Fprintf(w, "Batch %s (%s): %d onboardings: %d applied, %d already done, %d finished, %d failed",
+			op.ID, op.Action, len(results), summary[batch.StatusApplied], summary[batch.StatusDone],
+			summary[batch.StatusFinished], summary[batch.StatusFailed])
+		if r.DryRun {
+			fmt.Fprintf(w, ", %d planned (dry run)", summary[batch.StatusPlanned])
+		}
+		fmt.Fprintln(w)
+		if summary[batch.StatusFailed] > 0 && *checkpoint != "" {
+			fmt.Fprintf(w, "Rerun with -checkpoint %s to retry the failures.\n", *checkpoint)
+		}
+	})
+
+	if summary[batch.StatusFailed] > 0 {
+		return errBatchIncomplete
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Package cli implements the onboarding command-line interface used by
+// operators and runbook scripts. Every subcommand addresses an onboarding by
+// merchant ID, can print JSON, and reports the outcome in its exit code.
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+// Exit codes.
+const (
+	ExitOK             = 0
+	ExitError          = 1 // Anything not covered below, e.g. the server is unreachable.
+	ExitUsage          = 2
+	ExitNotFound       = 3 // No onboarding for the merchant.
+	ExitAlreadyStarted = 4 // start: the merchant already has an onboarding.
+	ExitNotCompleted   = 5 // result: still running (and -wait not given).
+	ExitCancelled      = 6 // result: the onboarding was cancelled.
+	ExitImportErrors   = 7 // import: some rows were invalid or failed to start.
+	ExitBatchErrors    = 8 // batch: the operation failed for some onboardings.
+)
+
+// Service is the onboarding operations the CLI drives.
+// *onboarding.Service implements it.
+type Service interface {
+	Start(ctx context.Context, req shared.OnboardingRequest) (onboarding.Execution, error)
+	SubmitDocument(ctx context.Context, merchantID, documentID string) error
+	Status(ctx context.Context, merchantID string) (shared.OnboardingStatusResponse, error)
+	ExtendDeadline(ctx context.Context, merchantID string, ext shared.DeadlineExtension) error
+	ResendReminder(ctx context.Context, merchantID string, resend shared.ReminderResend) error
+	Cancel(ctx context.Context, merchantID string) error
+	Result(ctx context.Context, merchantID string, wait bool) (shared.OnboardingResult, error)
+	List(ctx context.Context, filter onboarding.ListFilter) ([]onboarding.Summary, error)
+}
+
+// CLI runs onboarding subcommands against a Service.
+type CLI struct {
+	Service Service
+	Stdout  io.Writer
+	Stderr  io.Writer
+
+	// Name is the program name shown in usage messages.
+	Name string
+}
+
+// errUsage marks errors that should print usage and exit with ExitUsage.
+var errUsage = errors.New("usage error")
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(c *CLI, ctx context.Context, out *output, args []string) error
+}
+
+var commands = []command{
+	{"start", "-merchant-id ID -email EMAIL -country CC [merchant flags] | -file request.json", "Start onboarding for a merchant", (*CLI).start},
+	{"submit", "MERCHANT_ID DOCUMENT_ID", "Submit the merchant's identity document", (*CLI).submit},
+	{"status", "MERCHANT_ID", "Show the onboarding's current status", (*CLI).status},
+	{"extend", "MERCHANT_ID -days N [-reason TEXT]", "Extend the document deadline", (*CLI).extend},
+	{"cancel", "MERCHANT_ID", "Cancel the onboarding", (*CLI).cancel},
+	{"result", "MERCHANT_ID [-wait]", "Show the onboarding's result", (*CLI).result},
+	{"list", "[-onboarding-status STATUS] [-country CC] [-business-type TYPE] [-days-left N] [-status STATUS] [-limit N]", "List onboardings matching filters", (*CLI).list},
+	{"import", "FILE [-format csv|jsonl] [-rate N] [-concurrency N] [-report FILE] [-dry-run]", "Start onboarding for every merchant in a CSV or JSONL file", (*CLI).importMerchants},
+	{"batch", "extend|remind|cancel [list filters] [-query CLAUSE] [-days N] [-reason TEXT] [-rate N] [-concurrency N] [-max N] [-checkpoint FILE] [-dry-run]", "Apply an operation to every running onboarding matching filters", (*CLI).batch},
+}
+
+// Run executes the subcommand in args and returns the process exit code.
+func (c *CLI) Run(ctx context.Context, args []string) int {
+	global := flag.NewFlagSet(c.name(), flag.ContinueOnError)
+	global.SetOutput(io.Discard)
+	format := global.String("output", "text", "output format: text or json")
+	if err := global.Parse(args); err != nil || global.NArg() == 0 || !validFormat(*format) {
+		c.usage()
+		return ExitUsage
+	}
+
+	name, rest := global.Arg(0), global.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		out := &output{stdout: c.Stdout, stderr: c.Stderr, format: *format}
+		err := cmd.run(c, ctx, out, rest)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(c.Stderr, "%v\n\nUsage: %s %s %s\n", err, c.name(), cmd.name, cmd.args)
+			return ExitUsage
+		}
+		if err != nil {
+			out.error(err)
+		}
+		return exitCode(err)
+	}
+
+	c.usage()
+	return ExitUsage
+}
+
+func (c *CLI) name() string {
+	if c.Name == "" {
+		return "onboarding"
+	}
+	return c.Name
+}
+
+func (c *CLI) usage() {
+	fmt.Fprintf(c.Stderr, "Usage: %s [-output text|json] COMMAND [ARGS]\n\nCommands:\n", c.name())
+	for _, cmd := range commands {
+		fmt.Fprintf(c.Stderr, "  %-8s %s\n", cmd.name, cmd.summary)
+	}
+}
+
+// errImportIncomplete is returned by import when some rows weren't started.
+var errImportIncomplete = errors.New("some rows were invalid or failed to start")
+
+// errBatchIncomplete is returned by batch when the operation failed for
+// some onboardings.
+var errBatchIncomplete = errors.New("the operation failed for some onboardings")
+
+// exitCode maps a command's error to the process exit code.
+func exitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, errImportIncomplete):
+		return ExitImportErrors
+	case errors.Is(err, errBatchIncomplete):
+		return ExitBatchErrors
+	case errors.Is(err, onboarding.ErrInvalidRequest):
+		return ExitUsage
+	case errors.Is(err, onboarding.ErrNotFound):
+		return ExitNotFound
+	case errors.Is(err, onboarding.ErrAlreadyStarted):
+		return ExitAlreadyStarted
+	case errors.Is(err, onboarding.ErrNotCompleted):
+		return ExitNotCompleted
+	case errors.Is(err, onboarding.ErrCancelled):
+		return ExitCancelled
+	default:
+		return ExitError
+	}
+}
+
+// newFlagSet returns a subcommand flag set that also accepts -output, so it
+// can be given before or after the subcommand.
+func newFlagSet(name string, out *output) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&out.format, "output", out.format, "output format: text or json")
+	return fs
+}
+
+// parseArgs parses flags that may appear before or after the positional
+// arguments and returns the positional arguments. want is the number of
+// positional arguments required.
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w: %v", errUsage, err)
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if format := fs.Lookup("output").Value.String(); !validFormat(format) {
+		return nil, fmt.Errorf("%w: unknown output format %q", errUsage, format)
+	}
+	if len(positional) != want {
+		return nil, fmt.Errorf("%w: expected %d argument(s), got %d", errUsage, want, len(positional))
+	}
+	for _, arg := range positional {
+		if strings.TrimSpace(arg) == "" {
+			return nil, fmt.Errorf("%w: empty argument", errUsage)
+		}
+	}
+	return positional, nil
+}
+
+func validFormat(format string) bool {
+	return format == "text" || format == "json"
+}
+
+// output prints command results as text or JSON.
+type output struct {
+	stdout io.Writer
+	stderr io.Writer
+	format string
+}
+
+// print writes v as indented JSON, or calls text in text mode.
+func (o *output) print(v interface{}, text func(w io.Writer)) {
+	if o.format == "json" {
+		enc := json.NewEncoder(o.stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(v)
+		return
+	}
+	text(o.stdout)
+}
+
+func (o *output) error(err error) {
+	if o.format == "json" {
+		_ = json.NewEncoder(o.stderr).Encode(map[string]interface{}{
+			"error":    err.Error(),
+			"exitCode": exitCode(err),
+		})
+		return
+	}
+	fmt.Fprintf(o.stderr, "error: %v\n", err)
+}
@@ -0,0 +1,271 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+func (c *CLI) start(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("start", out)
+	file := fs.String("file", "", "JSON file with an OnboardingRequest; flags override its merchant fields")
+	var m shared.MerchantInfo
+	fs.StringVar(&m.MerchantID, "merchant-id", "", "merchant ID")
+	fs.StringVar(&m.Name, "name", "", "merchant name")
+	fs.StringVar(&m.Email, "email", "", "contact email (required)")
+	fs.StringVar(&m.Phone, "phone", "", "contact phone, for SMS reminders")
+	fs.StringVar(&m.Country, "country", "", "two-letter ISO country code (required)")
+	fs.StringVar(&m.BusinessType, "business-type", "", "business type")
+	fs.StringVar(&m.RiskTier, "risk-tier", "", "risk tier: low, standard or high")
+	fs.StringVar(&m.Timezone, "timezone", "", "IANA timezone; derived from -country when empty")
+	fs.StringVar(&m.AccountManagerEmail, "account-manager", "", "account manager email")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+
+	var req shared.OnboardingRequest
+	if *file != "" {
+		data, err := os.ReadFile(*file)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		if err := json.Unmarshal(data, &req); err != nil {
+			return fmt.Errorf("%w: %s: %v", errUsage, *file, err)
+		}
+	}
+	overrideMerchant(&req.Merchant, m)
+	if req.Merchant.MerchantID == "" {
+		return fmt.Errorf("%w: -merchant-id is required", errUsage)
+	}
+
+	exec, err := c.Service.Start(ctx, req)
+	if err != nil {
+		return err
+	}
+	out.print(exec, func(w io.Writer) {
+		fmt.Fprintf(w, "Started onboarding for %s\n  WorkflowID: %s\n  RunID:      %s\n",
+			req.Merchant.MerchantID, exec.WorkflowID, exec.RunID)
+	})
+	return nil
+}
+
+// overrideMerchant copies the non-empty fields of flags onto m.
+func overrideMerchant(m *shared.MerchantInfo, flags shared.MerchantInfo) {
+	for _, f := range []struct{ dst, src *string }{
+		{&m.MerchantID, &flags.MerchantID},
+		{&m.Name, &flags.Name},
+		{&m.Email, &flags.Email},
+		{&m.Phone, &flags.Phone},
+		{&m.Country, &flags.Country},
+		{&m.BusinessType, &flags.BusinessType},
+		{&m.RiskTier, &flags.RiskTier},
+		{&m.Timezone, &flags.Timezone},
+		{&m.AccountManagerEmail, &flags.AccountManagerEmail},
+	} {
+		if *f.src != "" {
+			*f.dst = *f.src
+		}
+	}
+}
+
+func (c *CLI) submit(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("submit", out)
+	pos, err := parseArgs(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	merchantID, documentID := pos[0], pos[1]
+
+	if err := c.Service.SubmitDocument(ctx, merchantID, documentID); err != nil {
+		return err
+	}
+	out.print(map[string]string{"merchantId": merchantID, "documentId": documentID}, func(w io.Writer) {
+		fmt.Fprintf(w, "Submitted document %s for %s\n", documentID, merchantID)
+	})
+	return nil
+}
+
+func (c *CLI) status(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("status", out)
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	status, err := c.Service.Status(ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	out.print(status, func(w io.Writer) {
+		fmt.Fprintf(w, "Status:      %s\n", status.Status)
+		fmt.Fprintf(w, "Deadline:    %s (%d days remaining)\n", status.Deadline.Format(time.RFC3339), status.DaysRemaining)
+		fmt.Fprintf(w, "Restriction: %s\n", status.Restriction)
+		fmt.Fprintf(w, "Payments:    %d\n", status.PaymentsSeen)
+		if status.EarlyKYCTriggered {
+			fmt.Fprintf(w, "Early KYC:   triggered by processed volume %v\n", status.ProcessedVolume)
+		}
+		if status.Escalated {
+			fmt.Fprintln(w, "Escalated:   yes")
+		}
+		if cond := status.Conditions; cond != nil {
+			fmt.Fprintf(w, "Conditions:  %s\n", formatConditions(*cond))
+		}
+	})
+	return nil
+}
+
+func (c *CLI) extend(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("extend", out)
+	var ext shared.DeadlineExtension
+	fs.IntVar(&ext.Days, "days", 0, "days to add to the deadline")
+	fs.StringVar(&ext.Reason, "reason", "", "reason, recorded in the workflow log")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if ext.Days <= 0 {
+		return fmt.Errorf("%w: -days must be positive", errUsage)
+	}
+
+	if err := c.Service.ExtendDeadline(ctx, pos[0], ext); err != nil {
+		return err
+	}
+	out.print(ext, func(w io.Writer) {
+		fmt.Fprintf(w, "Requested a %d-day deadline extension for %s\n", ext.Days, pos[0])
+	})
+	return nil
+}
+
+func (c *CLI) cancel(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("cancel", out)
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	if err := c.Service.Cancel(ctx, pos[0]); err != nil {
+		return err
+	}
+	out.print(map[string]string{"merchantId": pos[0]}, func(w io.Writer) {
+		fmt.Fprintf(w, "Requested cancellation of onboarding for %s\n", pos[0])
+	})
+	return nil
+}
+
+func (c *CLI) result(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("result", out)
+	wait := fs.Bool("wait", false, "wait for the onboarding to finish")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.Service.Result(ctx, pos[0], *wait)
+	if err != nil {
+		return err
+	}
+	out.print(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Outcome:      %s\n", result.Outcome)
+		fmt.Fprintf(w, "Merchant:     %s\n", result.MerchantID)
+		if !result.StartedAt.IsZero() {
+			fmt.Fprintf(w, "Started:      %s\n", result.StartedAt.Format(time.RFC3339))
+		}
+		if result.DocumentSubmittedAt != nil {
+			fmt.Fprintf(w, "Submitted:    %s\n", result.DocumentSubmittedAt.Format(time.RFC3339))
+		}
+		if !result.DecidedAt.IsZero() {
+			fmt.Fprintf(w, "Decided:      %s\n", result.DecidedAt.Format(time.RFC3339))
+		}
+		if result.VerificationID != "" {
+			fmt.Fprintf(w, "Verification: %s\n", result.VerificationID)
+		}
+		if len(result.ReasonCodes) > 0 {
+			fmt.Fprintf(w, "Reasons:      %s\n", strings.Join(result.ReasonCodes, ", "))
+		}
+		if cond := result.Conditions; cond != nil {
+			fmt.Fprintf(w, "Conditions:   %s\n", formatConditions(*cond))
+		}
+		if len(result.RemindersSent) > 0 {
+			fmt.Fprintf(w, "Reminders:    %s\n", strings.Join(result.RemindersSent, ", "))
+		}
+		if len(result.RestrictionsApplied) > 0 {
+			fmt.Fprintf(w, "Restrictions: %v\n", result.RestrictionsApplied)
+		}
+	})
+	return nil
+}
+
+func (c *CLI) list(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("list", out)
+	var filter onboarding.ListFilter
+	var daysLeft int
+	fs.StringVar(&filter.Status, "status", "", "execution status, e.g. Running, Completed, Canceled")
+	fs.StringVar(&filter.OnboardingStatus, "onboarding-status", "", "onboarding status, e.g. AWAITING_KYC_DOCUMENTS, KYC_IN_PROGRESS")
+	fs.StringVar(&filter.Country, "country", "", "ISO country code")
+	fs.StringVar(&filter.BusinessType, "business-type", "", "business type")
+	fs.IntVar(&daysLeft, "days-left", 0, "only onboardings with fewer than N days until the deadline")
+	fs.IntVar(&filter.Limit, "limit", 100, "maximum number of onboardings to list")
+	if _, err := parseArgs(fs, args, 0); err != nil {
+		return err
+	}
+	if daysLeft < 0 {
+		return fmt.Errorf("%w: -days-left must not be negative", errUsage)
+	}
+	filter.DeadlineWithin = time.Duration(daysLeft) * 24 * time.Hour
+
+	summaries, err := c.Service.List(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if summaries == nil {
+		summaries = []onboarding.Summary{}
+	}
+	out.print(summaries, func(w io.Writer) {
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "MERCHANT\tCOUNTRY\tONBOARDING STATUS\tDEADLINE\tKYC ATTEMPTS\tEXECUTION\tSTARTED")
+		for _, s := range summaries {
+			deadline := "-"
+			if s.Deadline != nil {
+				deadline = s.Deadline.Format(time.DateOnly)
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\t%s\n",
+				s.MerchantID, orDash(s.Country), orDash(string(s.OnboardingStatus)), deadline,
+				s.KYCAttempts, s.Status, s.StartTime.Format(time.RFC3339))
+		}
+		tw.Flush()
+	})
+	return nil
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func formatConditions(c shared.MerchantConditions) string {
+	var parts []string
+	if c.MonthlyVolumeLimit > 0 {
+		parts = append(parts, fmt.Sprintf("monthly volume limit %d", c.MonthlyVolumeLimit))
+	}
+	if c.NoThirdPartyPayouts {
+		parts = append(parts, "no third-party payouts")
+	}
+	if c.EnhancedMonitoring {
+		parts = append(parts, "enhanced monitoring")
+	}
+	s := strings.Join(parts, ", ")
+	if c.Reason != "" {
+		s += " (" + c.Reason + ")"
+	}
+	return s
+}
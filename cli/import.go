@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"temporal-customer-onboarding/bulkimport"
+)
+
+func (c *CLI) importMerchants(ctx context.Context, out *output, args []string) error {
+	fs := newFlagSet("import", out)
+	format := fs.String("format", "", "input format: csv or jsonl (default: from the file extension)")
+	report := fs.String("report", "", "write the per-row report to this CSV file")
+	im := &bulkimport.Importer{Starter: c.Service}
+	fs.Float64Var(&im.Rate, "rate", 10, "maximum starts per second; 0 for unlimited")
+	fs.IntVar(&im.Concurrency, "concurrency", 4, "starts in flight at once")
+	fs.BoolVar(&im.DryRun, "dry-run", false, "validate rows without starting anything")
+	pos, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := pos[0]
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+	if im.Concurrency <= 0 || im.Rate < 0 {
+		return fmt.Errorf("%w: -concurrency must be positive and -rate not negative", errUsage)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	defer f.Close()
+
+	var rows []bulkimport.Row
+	switch *format {
+	case "csv":
+		rows, err = bulkimport.ReadCSV(f)
+	case "jsonl", "ndjson":
+		rows, err = bulkimport.ReadJSONL(f)
+	default:
+		return fmt.Errorf("%w: unknown format %q, use -format csv or jsonl", errUsage, *format)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", errUsage, path, err)
+	}
+
+	results := im.Run(ctx, rows)
+
+	if *report != "" {
+		rf, err := os.Create(*report)
+		if err != nil {
+			return err
+		}
+		err = bulkimport.WriteReport(rf, results)
+		if closeErr := rf.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("write report: %w", err)
+		}
+	}
+
+	summary := bulkimport.Summary(results)
+	out.print(struct {
+		Summary map[bulkimport.Status]int `json:"summary"`
+		Results []bulkimport.Result       `json:"results"`
+	}{summary, results}, func(w io.Writer) {
+		for _, r := range results {
+			if r.Error != "" {
+				fmt.Fprintf(w, "line %d (%s): %s: %s\n", r.Line, r.MerchantID, r.Status, r.Error)
+			}
+		}
+		fmt.Fprintf(w, "%d rows: %d started, %d skipped, %d invalid, %d failed",
+			len(results), summary[bulkimport.StatusStarted], summary[bulkimport.StatusSkipped],
+			summary[bulkimport.StatusInvalid], summary[bulkimport.StatusFailed])
+		if im.DryRun {
+			fmt.Fprintf(w, ", %d valid (dry run)", summary[bulkimport.StatusValid])
+		}
+		fmt.Fprintln(w)
+	})
+
+	if summary[bulkimport.StatusInvalid] > 0 || summary[bulkimport.StatusFailed] > 0 {
+		return errImportIncomplete
+	}
+	return nil
+}
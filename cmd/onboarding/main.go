@@ -0,0 +1,108 @@
+// Command onboarding runs the onboarding workers for local development and
+// small deployments: the workflow worker, the activity worker or both in one
+// process, optionally serving the HTTP API too, all on one Temporal client.
+//
+//	onboarding [-api] [-api-addr ADDR] [workflow|activity|all]
+//
+// The role defaults to all. Production deployments can keep running
+// workers/onboarding, workers/activity and apiserver separately.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"temporal-customer-onboarding/api"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/tracing"
+	"temporal-customer-onboarding/workers"
+)
+
+func main() {
+	serveAPI := flag.Bool("api", false, "also serve the onboarding HTTP API (requires API_TOKEN)")
+	apiAddr := flag.String("api-addr", envOr("API_ADDR", ":8080"), "listen address for the HTTP API")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-api] [-api-addr ADDR] [workflow|activity|all]\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	role := "all"
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		role = flag.Arg(0)
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	roles, err := workers.ParseRoles(role)
+	if err != nil {
+		log.Printf("%v", err)
+		flag.Usage()
+		os.Exit(2)
+	}
+	token := os.Getenv("API_TOKEN")
+	if *serveAPI && token == "" {
+		log.Fatal("API_TOKEN must be set to the bearer token the merchant portal presents")
+	}
+	if *serveAPI && os.Getenv("STATUS_EVENTS_TOKEN") == token {
+		log.Fatal("STATUS_EVENTS_TOKEN must differ from API_TOKEN")
+	}
+
+	p, err := workers.NewProcess("onboarding")
+	if err != nil {
+		log.Fatalf("Unable to start: %v", err)
+	}
+	defer p.Close()
+
+	// One listener per process: metrics and probes use the addresses
+	// configured for the first role.
+	o := p.WorkerOptions(roles[0])
+	p.Serve(o.MetricsAddress, o.HealthAddress)
+
+	for _, r := range roles {
+		if err := p.Add(r); err != nil {
+			log.Fatalf("Unable to create %s worker: %v", r, err)
+		}
+	}
+
+	if *serveAPI {
+		// The API starts and signals onboardings through the workers'
+		// client, routed to the configured task queues.
+		svc := onboarding.NewService(p.Client)
+		svc.TaskQueue = p.Config.TaskQueues.Workflow
+		svc.ActivityTaskQueue = p.Config.TaskQueues.Activity
+		server := api.NewServer(svc, token)
+		server.StatusEventsToken = os.Getenv("STATUS_EVENTS_TOKEN")
+		srv := &http.Server{
+			Addr:              *apiAddr,
+			Handler:           tracing.Handler(server, "onboarding-api"),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		go func() {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Fatalf("API server stopped: %v", err)
+			}
+		}()
+		defer srv.Close()
+		log.Printf("Serving the onboarding API on %s (spec at /openapi.yaml)", *apiAddr)
+	}
+
+	// SIGTERM drains every worker in the process together.
+	if err := p.Run(); err != nil {
+		log.Fatalf("Unable to start workers: %v", err)
+	}
+}
+
+func envOr(key, fallback string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
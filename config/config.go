@@ -0,0 +1,288 @@
+// Package config loads the settings every binary shares: the Temporal
+// connection (server address, namespace, mTLS, API key, identity and payload
+// encryption), task queue names, worker tuning, logging and trace export.
+// Settings come from an optional YAML file, named by ONBOARDING_CONFIG, with
+// environment variables taking precedence; the variable names match the
+// temporal CLI's and OpenTelemetry's.
+package config
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"gopkg.in/yaml.v3"
+)
+
+// FileEnv names the environment variable holding the config file path.
+const FileEnv = "ONBOARDING_CONFIG"
+
+// Config is the configuration file's top level.
+type Config struct {
+	Temporal   Temporal   `yaml:"temporal"`
+	TaskQueues TaskQueues `yaml:"taskQueues"`
+	Workers    Workers    `yaml:"workers"`
+	Logging    Logging    `yaml:"logging"`
+	Tracing    Tracing    `yaml:"tracing"`
+}
+
+// Temporal is how to reach and authenticate to the Temporal service.
+type Temporal struct {
+	// HostPort is the frontend address; defaults to localhost:7233.
+	HostPort string `yaml:"hostPort"`
+	// Namespace defaults to "default".
+	Namespace string `yaml:"namespace"`
+	// Identity names this client in workflow histories; the SDK defaults
+	// to pid@hostname.
+	Identity string `yaml:"identity"`
+	// APIKey authenticates to Temporal Cloud. It enables TLS.
+	APIKey        string        `yaml:"apiKey"`
+	TLS           TLS           `yaml:"tls"`
+	DataConverter DataConverter `yaml:"dataConverter"`
+}
+
+// TLS configures the connection's TLS. Setting any file enables it.
+type TLS struct {
+	Enabled bool `yaml:"enabled"`
+	// CertFile and KeyFile are the client certificate for mTLS.
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+	// CAFile verifies the server instead of the system roots.
+	CAFile     string `yaml:"caFile"`
+	ServerName string `yaml:"serverName"`
+	// DisableHostVerification skips server certificate verification.
+	// Local testing only.
+	DisableHostVerification bool `yaml:"disableHostVerification"`
+}
+
+// DataConverter configures how payloads are encoded.
+type DataConverter struct {
+	// EncryptionKeyFile, if set, holds a base64-encoded 256-bit AES key;
+	// payloads are then encrypted before they leave the process. Every
+	// binary that reads workflow data needs the same key.
+	EncryptionKeyFile string `yaml:"encryptionKeyFile"`
+	// EncryptionKeyID is recorded with each encrypted payload; defaults to
+	// "default".
+	EncryptionKeyID string `yaml:"encryptionKeyId"`
+}
+
+// Load reads the file named by ONBOARDING_CONFIG, if any, applies
+// environment overrides and validates the result.
+func Load() (Config, error) {
+	return LoadFrom(os.Getenv(FileEnv), os.LookupEnv)
+}
+
+// LoadFrom reads the YAML file at path, if path isn't empty, applies
+// overrides from lookupEnv and validates the result.
+func LoadFrom(path string, lookupEnv func(string) (string, bool)) (Config, error) {
+	cfg := Config{Temporal: Temporal{
+		HostPort:  client.DefaultHostPort,
+		Namespace: client.DefaultNamespace,
+	}}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return Config{}, fmt.Errorf("read config: %w", err)
+		}
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return Config{}, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	if err := cfg.applyEnv(lookupEnv); err != nil {
+		return Config{}, err
+	}
+	cfg.TaskQueues = cfg.TaskQueues.withDefaults()
+	if err := cfg.Validate(); err != nil {
+		if path != "" {
+			return Config{}, fmt.Errorf("config %s: %w", path, err)
+		}
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// applyEnv overrides settings from environment variables.
+func (c *Config) applyEnv(lookupEnv func(string) (string, bool)) error {
+	t := &c.Temporal
+	for name, dst := range map[string]*string{
+		"TEMPORAL_ADDRESS":                 &t.HostPort,
+		"TEMPORAL_NAMESPACE":               &t.Namespace,
+		"TEMPORAL_IDENTITY":                &t.Identity,
+		"TEMPORAL_API_KEY":                 &t.APIKey,
+		"TEMPORAL_TLS_CLIENT_CERT_PATH":    &t.TLS.CertFile,
+		"TEMPORAL_TLS_CLIENT_KEY_PATH":     &t.TLS.KeyFile,
+		"TEMPORAL_TLS_SERVER_CA_CERT_PATH": &t.TLS.CAFile,
+		"TEMPORAL_TLS_SERVER_NAME":         &t.TLS.ServerName,
+		"ONBOARDING_ENCRYPTION_KEY_FILE":   &t.DataConverter.EncryptionKeyFile,
+		"ONBOARDING_ENCRYPTION_KEY_ID":     &t.DataConverter.EncryptionKeyID,
+		"ONBOARDING_WORKFLOW_TASK_QUEUE":   &c.TaskQueues.Workflow,
+		"ONBOARDING_ACTIVITY_TASK_QUEUE":   &c.TaskQueues.Activity,
+		"ONBOARDING_WORKFLOW_METRICS_ADDR": &c.Workers.Workflow.MetricsAddress,
+		"ONBOARDING_ACTIVITY_METRICS_ADDR": &c.Workers.Activity.MetricsAddress,
+		"ONBOARDING_WORKFLOW_HEALTH_ADDR":  &c.Workers.Workflow.HealthAddress,
+		"ONBOARDING_ACTIVITY_HEALTH_ADDR":  &c.Workers.Activity.HealthAddress,
+		"ONBOARDING_LOG_FORMAT":            &c.Logging.Format,
+		"ONBOARDING_LOG_LEVEL":             &c.Logging.Level,
+		"OTEL_TRACES_EXPORTER":             &c.Tracing.Exporter,
+		"OTEL_EXPORTER_OTLP_ENDPOINT":      &c.Tracing.Endpoint,
+	} {
+		if v, ok := lookupEnv(name); ok && v != "" {
+			*dst = v
+		}
+	}
+	for name, dst := range map[string]*bool{
+		"TEMPORAL_TLS":                           &t.TLS.Enabled,
+		"TEMPORAL_TLS_DISABLE_HOST_VERIFICATION": &t.TLS.DisableHostVerification,
+	} {
+		v, ok := lookupEnv(name)
+		if !ok || v == "" {
+			continue
+		}
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: want true or false, got %q", name, v)
+		}
+		*dst = b
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once.
+func (c Config) Validate() error {
+	t := c.Temporal
+	var errs []error
+	if _, port, err := net.SplitHostPort(t.HostPort); err != nil || port == "" {
+		errs = append(errs, fmt.Errorf("temporal.hostPort: want host:port, got %q", t.HostPort))
+	}
+	if strings.TrimSpace(t.Namespace) == "" {
+		errs = append(errs, errors.New("temporal.namespace must not be empty"))
+	}
+	if (t.TLS.CertFile == "") != (t.TLS.KeyFile == "") {
+		errs = append(errs, errors.New("temporal.tls: certFile and keyFile must be set together"))
+	}
+	for _, f := range []struct{ name, path string }{
+		{"temporal.tls.certFile", t.TLS.CertFile},
+		{"temporal.tls.keyFile", t.TLS.KeyFile},
+		{"temporal.tls.caFile", t.TLS.CAFile},
+		{"temporal.dataConverter.encryptionKeyFile", t.DataConverter.EncryptionKeyFile},
+	} {
+		if f.path == "" {
+			continue
+		}
+		if _, err := os.Stat(f.path); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+		}
+	}
+	errs = append(errs, c.TaskQueues.validate()...)
+	errs = append(errs, c.Workers.Workflow.validate("workflow")...)
+	errs = append(errs, c.Workers.Activity.validate("activity")...)
+	errs = append(errs, c.Logging.validate()...)
+	errs = append(errs, c.Tracing.validate()...)
+	return errors.Join(errs...)
+}
+
+// tlsEnabled reports whether the connection uses TLS.
+func (t Temporal) tlsEnabled() bool {
+	return t.TLS.Enabled || t.TLS.CertFile != "" || t.TLS.CAFile != "" ||
+		t.TLS.ServerName != "" || t.TLS.DisableHostVerification
+}
+
+// ClientOptions returns the client options for the configuration, loading
+// certificates and keys. Callers add their own Logger, MetricsHandler and
+// interceptors.
+func (c Config) ClientOptions() (client.Options, error) {
+	t := c.Temporal
+	opts := client.Options{
+		HostPort:  t.HostPort,
+		Namespace: t.Namespace,
+		Identity:  t.Identity,
+	}
+	if t.APIKey != "" {
+		// The SDK enables TLS with the system roots when an API key is set
+		// and no TLS config is given.
+		opts.Credentials = client.NewAPIKeyStaticCredentials(t.APIKey)
+	}
+	if t.tlsEnabled() {
+		tlsConfig, err := t.TLS.config()
+		if err != nil {
+			return client.Options{}, err
+		}
+		opts.ConnectionOptions.TLS = tlsConfig
+	}
+	if path := t.DataConverter.EncryptionKeyFile; path != "" {
+		codec, err := NewEncryptionCodecFromFile(path, t.DataConverter.EncryptionKeyID)
+		if err != nil {
+			return client.Options{}, err
+		}
+		opts.DataConverter = converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+	}
+	return opts, nil
+}
+
+func (t TLS) config() (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         t.ServerName,
+		InsecureSkipVerify: t.DisableHostVerification,
+	}
+	if t.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(t.CertFile, t.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("temporal.tls: load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	if t.CAFile != "" {
+		pem, err := os.ReadFile(t.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("temporal.tls.caFile: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("temporal.tls.caFile: no PEM certificates in %s", t.CAFile)
+		}
+		cfg.RootCAs = pool
+	}
+	return cfg, nil
+}
+
+// Dial loads the configuration and connects to Temporal. extra, if set,
+// adjusts the options first, e.g. to add a logger.
+func Dial(extra func(*client.Options)) (client.Client, Config, error) {
+	cfg, err := Load()
+	if err != nil {
+		return nil, Config{}, err
+	}
+	c, err := cfg.Dial(extra)
+	if err != nil {
+		return nil, Config{}, err
+	}
+	return c, cfg, nil
+}
+
+// Dial connects to Temporal. extra, if set, adjusts the options first.
+func (c Config) Dial(extra func(*client.Options)) (client.Client, error) {
+	opts, err := c.ClientOptions()
+	if err != nil {
+		return nil, err
+	}
+	if extra != nil {
+		extra(&opts)
+	}
+	tc, err := client.Dial(opts)
+	if err != nil {
+		return nil, fmt.Errorf("connect to %s (namespace %s): %w", c.Temporal.HostPort, c.Temporal.Namespace, err)
+	}
+	return tc, nil
+}
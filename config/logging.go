@@ -0,0 +1,41 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Logging configures the workers' logs.
+type Logging struct {
+	// Format is "json" (the default) or "text".
+	Format string `yaml:"format"`
+	// Level is debug, info (the default), warn or error.
+	Level string `yaml:"level"`
+}
+
+// SlogLevel returns the configured level.
+func (l Logging) SlogLevel() slog.Level {
+	var level slog.Level
+	if l.Level != "" {
+		// Validated when loading.
+		_ = level.UnmarshalText([]byte(l.Level))
+	}
+	return level
+}
+
+func (l Logging) validate() []error {
+	var errs []error
+	switch strings.ToLower(l.Format) {
+	case "", "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("logging.format: want json or text, got %q", l.Format))
+	}
+	if l.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(l.Level)); err != nil {
+			errs = append(errs, fmt.Errorf("logging.level: want debug, info, warn or error, got %q", l.Level))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Trace exporters.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Tracing configures OpenTelemetry trace export; see the tracing package.
+type Tracing struct {
+	// Exporter is "none" (the default), "stdout" or "otlp". "console", the
+	// name OTEL_TRACES_EXPORTER uses, is accepted for stdout.
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the OTLP/HTTP collector URL; defaults to
+	// http://localhost:4318.
+	Endpoint string `yaml:"endpoint"`
+}
+
+// ExporterName returns the normalized exporter name.
+func (t Tracing) ExporterName() string {
+	switch t.Exporter {
+	case "":
+		return ExporterNone
+	case "console":
+		return ExporterStdout
+	}
+	return t.Exporter
+}
+
+func (t Tracing) validate() []error {
+	var errs []error
+	switch t.ExporterName() {
+	case ExporterNone, ExporterStdout, ExporterOTLP:
+	default:
+		errs = append(errs, fmt.Errorf("tracing.exporter: want none, stdout or otlp, got %q", t.Exporter))
+	}
+	if t.Endpoint != "" {
+		if u, err := url.Parse(t.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("tracing.endpoint: want an http(s) URL, got %q", t.Endpoint))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/worker"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// TaskQueues names the task queues. Workers poll them and the services
+// that start onboardings route to them, so every binary in a deployment
+// must agree.
+type TaskQueues struct {
+	// Workflow is polled by the workflow worker; defaults to
+	// shared.OnboardingWorkflowTaskQueue.
+	Workflow string `yaml:"workflow"`
+	// Activity is polled by the activity worker; defaults to
+	// shared.ActivityTaskQueue. It travels to the workflows in each
+	// OnboardingRequest.
+	Activity string `yaml:"activity"`
+}
+
+// Workers holds the tuning for each worker. Zero values leave the SDK
+// defaults.
+type Workers struct {
+	Workflow WorkerOptions `yaml:"workflow"`
+	Activity WorkerOptions `yaml:"activity"`
+}
+
+// WorkerOptions is the subset of worker.Options deployments tune.
+type WorkerOptions struct {
+	// MaxConcurrentWorkflowTasks caps workflow tasks executing at once.
+	MaxConcurrentWorkflowTasks int `yaml:"maxConcurrentWorkflowTasks"`
+	// MaxConcurrentActivities caps activities executing at once on this
+	// worker; lower it to protect the KYC supplier and payments API.
+	MaxConcurrentActivities int `yaml:"maxConcurrentActivities"`
+	// WorkflowPollers and ActivityPollers are the goroutines polling for
+	// tasks.
+	WorkflowPollers int `yaml:"workflowPollers"`
+	ActivityPollers int `yaml:"activityPollers"`
+	// ActivitiesPerSecond rate limits activities started by this worker.
+	ActivitiesPerSecond float64 `yaml:"activitiesPerSecond"`
+	// TaskQueueActivitiesPerSecond is enforced by the server across every
+	// worker on the task queue.
+	TaskQueueActivitiesPerSecond float64 `yaml:"taskQueueActivitiesPerSecond"`
+	// StickyScheduleToStartTimeout is how long a workflow task waits for
+	// the worker with the workflow cached before going to another.
+	StickyScheduleToStartTimeout time.Duration `yaml:"stickyScheduleToStartTimeout"`
+	// StopTimeout is how long in-flight activities get to finish on
+	// SIGTERM or SIGINT before their contexts are cancelled.
+	StopTimeout time.Duration `yaml:"stopTimeout"`
+	// MetricsAddress, e.g. ":9090", serves Prometheus metrics at /metrics.
+	// Empty disables the endpoint.
+	MetricsAddress string `yaml:"metricsAddress"`
+	// HealthAddress serves the /livez and /readyz probes; the same address
+	// as MetricsAddress shares the listener. Empty disables the probes.
+	HealthAddress string `yaml:"healthAddress"`
+}
+
+// Options returns the worker options.
+func (o WorkerOptions) Options() worker.Options {
+	return worker.Options{
+		MaxConcurrentWorkflowTaskExecutionSize: o.MaxConcurrentWorkflowTasks,
+		MaxConcurrentActivityExecutionSize:     o.MaxConcurrentActivities,
+		MaxConcurrentWorkflowTaskPollers:       o.WorkflowPollers,
+		MaxConcurrentActivityTaskPollers:       o.ActivityPollers,
+		WorkerActivitiesPerSecond:              o.ActivitiesPerSecond,
+		TaskQueueActivitiesPerSecond:           o.TaskQueueActivitiesPerSecond,
+		StickyScheduleToStartTimeout:           o.StickyScheduleToStartTimeout,
+		WorkerStopTimeout:                      o.StopTimeout,
+	}
+}
+
+func (q TaskQueues) withDefaults() TaskQueues {
+	if q.Workflow == "" {
+		q.Workflow = shared.OnboardingWorkflowTaskQueue
+	}
+	if q.Activity == "" {
+		q.Activity = shared.ActivityTaskQueue
+	}
+	return q
+}
+
+func (q TaskQueues) validate() []error {
+	var errs []error
+	for _, f := range []struct{ name, queue string }{
+		{"taskQueues.workflow", q.Workflow},
+		{"taskQueues.activity", q.Activity},
+	} {
+		if strings.ContainsAny(f.queue, " \t\r\n") {
+			errs = append(errs, fmt.Errorf("%s: %q must not contain whitespace", f.name, f.queue))
+		}
+	}
+	return errs
+}
+
+func (o WorkerOptions) validate(name string) []error {
+	var errs []error
+	for _, f := range []struct {
+		field string
+		value float64
+	}{
+		{"maxConcurrentWorkflowTasks", float64(o.MaxConcurrentWorkflowTasks)},
+		{"maxConcurrentActivities", float64(o.MaxConcurrentActivities)},
+		{"workflowPollers", float64(o.WorkflowPollers)},
+		{"activityPollers", float64(o.ActivityPollers)},
+		{"activitiesPerSecond", o.ActivitiesPerSecond},
+		{"taskQueueActivitiesPerSecond", o.TaskQueueActivitiesPerSecond},
+		{"stickyScheduleToStartTimeout", float64(o.StickyScheduleToStartTimeout)},
+		{"stopTimeout", float64(o.StopTimeout)},
+	} {
+		if f.value < 0 {
+			errs = append(errs, fmt.Errorf("workers.%s.%s must not be negative", name, f.field))
+		}
+	}
+	for _, f := range []struct{ field, addr string }{
+		{"metricsAddress", o.MetricsAddress},
+		{"healthAddress", o.HealthAddress},
+	} {
+		if f.addr == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(f.addr); err != nil {
+			errs = append(errs, fmt.Errorf("workers.%s.%s: want host:port, got %q", name, f.field, f.addr))
+		}
+	}
+	// The SDK panics on a single workflow poller.
+	if o.WorkflowPollers == 1 {
+		errs = append(errs, errors.New("workers."+name+".workflowPollers must be at least 2"))
+	}
+	return errs
+}
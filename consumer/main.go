@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+
+	"go.temporal.io/sdk/client"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/paymentevents"
+	"temporal-customer-onboarding/tracing"
+)
+
+func main() {
+	source := flag.String("source", "file", `event source: "file" (JSONL tail) or "http"`)
+	path := flag.String("file", "payment-events.jsonl", "JSONL file to tail when -source=file")
+	follow := flag.Bool("follow", true, "keep tailing the file for new events")
+	checkpoint := flag.String("checkpoint", "", `file recording how far -file has been delivered (default "<file>.offset")`)
+	addr := flag.String("addr", ":8092", "listen address when -source=http (POST /events)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	tracer, err := tracing.Setup(context.Background(), cfg.Tracing, "onboarding-consumer")
+	if err != nil {
+		log.Fatalf("Unable to set up tracing: %v", err)
+	}
+	defer tracer.Shutdown(context.Background())
+	c, err := cfg.Dial(func(opts *client.Options) {
+		opts.Interceptors = append(opts.Interceptors, tracer.Interceptors...)
+	})
+	if err != nil {
+		log.Fatalf("Unable to create Temporal client: %v", err)
+	}
+	defer c.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var src paymentevents.Source
+	switch *source {
+	case "file":
+		// Resume after the last delivered event on restart.
+		if *checkpoint == "" {
+			*checkpoint = *path + ".offset"
+		}
+		fileSrc, err := paymentevents.OpenFileSource(*path, *follow, *checkpoint)
+		if err != nil {
+			log.Fatalf("Unable to open event source: %v", err)
+		}
+		defer fileSrc.Close()
+		src = fileSrc
+		log.Printf("Reading payment events from %s", *path)
+
+	case "http":
+		httpSrc := paymentevents.NewHTTPSource(1000)
+		mux := http.NewServeMux()
+		mux.Handle("/events", httpSrc)
+		server := &http.Server{Addr: *addr, Handler: mux}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Event endpoint stopped: %v", err)
+			}
+		}()
+		defer server.Close()
+		src = httpSrc
+		log.Printf("Accepting payment events on %s/events", *addr)
+
+	default:
+		log.Fatalf("Unknown source %q", *source)
+	}
+
+	consumer := paymentevents.NewConsumer(c)
+	consumer.TaskQueue = cfg.TaskQueues.Workflow
+	consumer.ActivityTaskQueue = cfg.TaskQueues.Activity
+	if err := consumer.Run(ctx, src); err != nil && ctx.Err() == nil {
+		log.Fatalf("Consumer stopped: %v", err)
+	}
+	log.Println("Payment event consumer stopped")
+}
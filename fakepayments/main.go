@@ -0,0 +1,33 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"temporal-customer-onboarding/payments"
+)
+
+// A local payment platform for development. Point the activity worker at it:
+//
+//	PAYMENTS_API_URL=http://localhost:8091 go run ./workers/activity/main.go
+func main() {
+	addr := os.Getenv("FAKE_PAYMENTS_ADDR")
+	if addr == "" {
+		addr = ":8091"
+	}
+
+	merchants := []string{"MERCH-001"}
+	if ids := os.Getenv("FAKE_PAYMENTS_MERCHANTS"); ids != "" {
+		merchants = strings.Split(ids, ",")
+	}
+
+	fake := payments.NewFakeServer(merchants...)
+	fake.Token = os.Getenv("PAYMENTS_API_TOKEN")
+
+	log.Printf("Fake payments API listening on %s (merchants: %s)", addr, strings.Join(merchants, ", "))
+	if err := http.ListenAndServe(addr, fake); err != nil {
+		log.Fatalf("Fake payments API stopped: %v", err)
+	}
+}
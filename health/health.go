@@ -0,0 +1,194 @@
+// Package health serves a worker's liveness and readiness probes and drains
+// it on shutdown. A worker is ready once it has connected to Temporal and
+// started polling, and stops being ready as soon as it starts draining, so
+// the orchestrator can tell a slow shutdown from a stuck one.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/worker"
+)
+
+// Checker tracks a worker's state and the activities it is running.
+type Checker struct {
+	ready    atomic.Bool
+	draining atomic.Bool
+	inFlight atomic.Int64
+}
+
+// NewChecker returns a Checker for a worker that hasn't started yet.
+func NewChecker() *Checker {
+	return &Checker{}
+}
+
+// SetReady marks the worker ready; call it once the worker has started.
+func (c *Checker) SetReady() {
+	c.ready.Store(true)
+}
+
+// Ready reports whether the worker is polling and not draining.
+func (c *Checker) Ready() bool {
+	return c.ready.Load() && !c.draining.Load()
+}
+
+// InFlight returns the number of activities executing.
+func (c *Checker) InFlight() int64 {
+	return c.inFlight.Load()
+}
+
+type status struct {
+	Status             string `json:"status"`
+	InFlightActivities int64  `json:"inFlightActivities"`
+}
+
+func (c *Checker) status() string {
+	switch {
+	case c.draining.Load():
+		return "draining"
+	case c.ready.Load():
+		return "ready"
+	}
+	return "starting"
+}
+
+// Handler serves GET /livez, which succeeds while the process is up, and
+// GET /readyz, which returns 503 until the worker is ready and again once
+// it starts draining. Both report the status and in-flight activities as
+// JSON.
+func (c *Checker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /livez", func(rw http.ResponseWriter, r *http.Request) {
+		c.writeStatus(rw, http.StatusOK)
+	})
+	mux.HandleFunc("GET /readyz", func(rw http.ResponseWriter, r *http.Request) {
+		code := http.StatusOK
+		if !c.Ready() {
+			code = http.StatusServiceUnavailable
+		}
+		c.writeStatus(rw, code)
+	})
+	return mux
+}
+
+func (c *Checker) writeStatus(rw http.ResponseWriter, code int) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	_ = json.NewEncoder(rw).Encode(status{Status: c.status(), InFlightActivities: c.InFlight()})
+}
+
+// Serve serves the probes, and any extra handlers by path, on addr in the
+// background, logging any error other than the server being closed.
+func (c *Checker) Serve(addr string, extra map[string]http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/", c.Handler())
+	for path, h := range extra {
+		mux.Handle(path, h)
+	}
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Health endpoint stopped", "error", err)
+		}
+	}()
+	return srv
+}
+
+// drainLogInterval is how often Run logs the in-flight activity count
+// while draining.
+const drainLogInterval = 5 * time.Second
+
+// Run starts workers, marks the process ready and blocks until interruptCh
+// delivers, e.g. worker.InterruptCh() on SIGINT or SIGTERM. It then drains
+// the workers together; stopTimeout, the longest of their
+// WorkerStopTimeouts, is how long in-flight activities have to finish.
+func (c *Checker) Run(interruptCh <-chan interface{}, stopTimeout time.Duration, workers ...worker.Worker) error {
+	for i, w := range workers {
+		if err := w.Start(); err != nil {
+			for _, started := range workers[:i] {
+				started.Stop()
+			}
+			return err
+		}
+	}
+	c.SetReady()
+	sig := <-interruptCh
+	slog.Info("Shutting down", "signal", fmt.Sprint(sig))
+	c.Drain(func() {
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.Stop()
+			}()
+		}
+		wg.Wait()
+	}, stopTimeout, drainLogInterval)
+	return nil
+}
+
+// Drain marks the worker draining and calls stop, which should stop the
+// worker and return once in-flight activities finish or the stop timeout
+// passes. While it waits, the in-flight activity count is logged every
+// interval.
+func (c *Checker) Drain(stop func(), stopTimeout, interval time.Duration) {
+	c.draining.Store(true)
+	slog.Info("Draining worker", "inFlightActivities", c.InFlight(), "stopTimeout", stopTimeout.String())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				slog.Info("Waiting for activities to finish", "inFlightActivities", c.InFlight())
+			}
+		}
+	}()
+
+	start := time.Now()
+	stop()
+	slog.Info("Worker stopped", "inFlightActivities", c.InFlight(), "drainedIn", time.Since(start).Round(time.Millisecond).String())
+}
+
+// Interceptor counts in-flight activities. Add it to the worker's
+// Interceptors.
+func (c *Checker) Interceptor() interceptor.WorkerInterceptor {
+	return &countingInterceptor{checker: c}
+}
+
+type countingInterceptor struct {
+	interceptor.WorkerInterceptorBase
+	checker *Checker
+}
+
+func (i *countingInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	a := &countingActivityInbound{checker: i.checker}
+	a.Next = next
+	return a
+}
+
+type countingActivityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+	checker *Checker
+}
+
+func (a *countingActivityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	a.checker.inFlight.Add(1)
+	defer a.checker.inFlight.Add(-1)
+	return a.Next.ExecuteActivity(ctx, in)
+}
@@ -0,0 +1,102 @@
+// Package logging builds the workers' log/slog loggers. Loggers from
+// workflow.GetLogger and activity.GetLogger already carry WorkflowType,
+// WorkflowID, RunID and Attempt, and ActivityType and ActivityID in
+// activities; the Interceptor adds the merchant ID, so a log pipeline can
+// follow one merchant's onboarding across both workers by filtering on
+// merchantId.
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/shared"
+)
+
+// MerchantIDKey is the log attribute holding the merchant ID.
+const MerchantIDKey = "merchantId"
+
+// New returns a logger writing to w in the configured format, JSON unless
+// text is asked for.
+func New(w io.Writer, cfg config.Logging) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: cfg.SlogLevel()}
+	if strings.EqualFold(cfg.Format, "text") {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// Interceptor adds the merchant ID to the loggers of onboarding and
+// identity verification workflows and their activities. It reads the ID
+// from the business workflow ID, so no payload needs decoding; other
+// workflows' loggers are unchanged.
+func Interceptor() interceptor.ClientInterceptor {
+	return &merchantLogInterceptor{}
+}
+
+type merchantLogInterceptor struct {
+	interceptor.InterceptorBase
+}
+
+func withMerchantID(logger log.Logger, workflowID string) log.Logger {
+	if id := shared.MerchantIDFromWorkflowID(workflowID); id != "" {
+		return log.With(logger, MerchantIDKey, id)
+	}
+	return logger
+}
+
+func (m *merchantLogInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	i := &workflowInbound{}
+	i.Next = next
+	return i
+}
+
+func (m *merchantLogInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &activityInbound{}
+	i.Next = next
+	return i
+}
+
+type workflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (w *workflowInbound) Init(outbound interceptor.WorkflowOutboundInterceptor) error {
+	i := &workflowOutbound{}
+	i.Next = outbound
+	return w.Next.Init(i)
+}
+
+type workflowOutbound struct {
+	interceptor.WorkflowOutboundInterceptorBase
+}
+
+func (w *workflowOutbound) GetLogger(ctx workflow.Context) log.Logger {
+	return withMerchantID(w.Next.GetLogger(ctx), workflow.GetInfo(ctx).WorkflowExecution.ID)
+}
+
+type activityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (a *activityInbound) Init(outbound interceptor.ActivityOutboundInterceptor) error {
+	i := &activityOutbound{}
+	i.Next = outbound
+	return a.Next.Init(i)
+}
+
+type activityOutbound struct {
+	interceptor.ActivityOutboundInterceptorBase
+}
+
+func (a *activityOutbound) GetLogger(ctx context.Context) log.Logger {
+	return withMerchantID(a.Next.GetLogger(ctx), activity.GetInfo(ctx).WorkflowExecution.ID)
+}
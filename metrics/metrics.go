@@ -0,0 +1,127 @@
+// Package metrics exports the SDK's worker metrics and the onboarding
+// business metrics to Prometheus. The SDK reports through the contrib/tally
+// metrics handler backed by a tally scope; the scope's Prometheus reporter
+// serves everything at /metrics.
+package metrics
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/uber-go/tally/v4"
+	tallyprom "github.com/uber-go/tally/v4/prometheus"
+	"go.temporal.io/sdk/client"
+	contribtally "go.temporal.io/sdk/contrib/tally"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// histogramBuckets, in seconds, cover request and task latencies.
+var histogramBuckets = []float64{
+	0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 300,
+}
+
+// timerBuckets overrides histogramBuckets for timers measuring something
+// other than a request.
+var timerBuckets = map[string]tally.DurationBuckets{
+	// Merchants take hours to months to submit documents.
+	shared.MetricDocumentSubmissionLatency: {
+		time.Hour, 6 * time.Hour, 24 * time.Hour, 3 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour,
+		30 * 24 * time.Hour, 60 * 24 * time.Hour, 90 * 24 * time.Hour,
+	},
+}
+
+// Prometheus collects metrics for one process. Pass Handler to
+// client.Options.MetricsHandler and serve the Prometheus value at /metrics.
+type Prometheus struct {
+	scope    tally.Scope
+	closer   io.Closer
+	reporter tallyprom.Reporter
+}
+
+// NewPrometheus returns a Prometheus backed by its own registry, so tests
+// and several workers in one process don't collide.
+func NewPrometheus() *Prometheus {
+	registry := prom.NewRegistry()
+	reporter := tallyprom.NewReporter(tallyprom.Options{
+		Registerer:              registry,
+		DefaultTimerType:        tallyprom.HistogramTimerType,
+		DefaultHistogramBuckets: histogramBuckets,
+		OnRegisterError: func(err error) {
+			log.Printf("Unable to register metric: %v", err)
+		},
+	})
+	scope, closer := tally.NewRootScope(tally.ScopeOptions{
+		CachedReporter:  reporter,
+		Separator:       tallyprom.DefaultSeparator,
+		SanitizeOptions: &contribtally.PrometheusSanitizeOptions,
+
+		OmitCardinalityMetrics: true,
+	}, time.Second)
+	return &Prometheus{scope: scope, closer: closer, reporter: reporter}
+}
+
+// Handler returns the SDK metrics handler. Names follow the Prometheus
+// conventions: counters end in _total and timers, which are reported in
+// seconds, in _seconds.
+func (p *Prometheus) Handler() client.MetricsHandler {
+	return contribtally.NewMetricsHandler(bucketedScope{contribtally.NewPrometheusNamingScope(p.scope)})
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format.
+func (p *Prometheus) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	p.reporter.HTTPHandler().ServeHTTP(rw, r)
+}
+
+// Serve serves /metrics on addr in the background, logging any error
+// other than the server being closed.
+func (p *Prometheus) Serve(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", p)
+	srv := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics endpoint stopped: %v", err)
+		}
+	}()
+	return srv
+}
+
+// Close flushes and stops the scope.
+func (p *Prometheus) Close() error {
+	return p.closer.Close()
+}
+
+// bucketedScope reports the timers in timerBuckets as histograms with their
+// own buckets.
+type bucketedScope struct {
+	tally.Scope
+}
+
+func (s bucketedScope) Timer(name string) tally.Timer {
+	if buckets, ok := timerBuckets[name]; ok {
+		return histogramTimer{s.Scope.Histogram(name, buckets)}
+	}
+	return s.Scope.Timer(name)
+}
+
+func (s bucketedScope) Tagged(tags map[string]string) tally.Scope {
+	return bucketedScope{s.Scope.Tagged(tags)}
+}
+
+func (s bucketedScope) SubScope(name string) tally.Scope {
+	return bucketedScope{s.Scope.SubScope(name)}
+}
+
+// histogramTimer records durations into a histogram.
+type histogramTimer struct {
+	tally.Histogram
+}
+
+func (t histogramTimer) Record(d time.Duration) {
+	t.RecordDuration(d)
+}
@@ -0,0 +1,338 @@
+// Package onboarding is the client-side API for merchant onboarding. It wraps
+// the Temporal client calls that start, signal, query and list
+// OnboardingWorkflow executions, addressing them by merchant ID.
+package onboarding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// Errors returned by Service methods.
+var (
+	ErrNotFound       = errors.New("onboarding not found")
+	ErrAlreadyStarted = errors.New("onboarding already started")
+	ErrNotCompleted   = errors.New("onboarding still running")
+	ErrCancelled      = errors.New("onboarding cancelled")
+	ErrInvalidRequest = errors.New("invalid request")
+)
+
+// Service drives onboarding workflows for merchants.
+type Service struct {
+	Client    client.Client
+	TaskQueue string
+	// ActivityTaskQueue is passed to the workflows it starts in
+	// OnboardingRequest.ActivityTaskQueue.
+	ActivityTaskQueue string
+}
+
+// NewService returns a Service starting workflows on the default task queues.
+func NewService(c client.Client) *Service {
+	return &Service{
+		Client:            c,
+		TaskQueue:         shared.OnboardingWorkflowTaskQueue,
+		ActivityTaskQueue: shared.ActivityTaskQueue,
+	}
+}
+
+// Execution identifies a started onboarding.
+type Execution struct {
+	WorkflowID string `json:"workflowId"`
+	RunID      string `json:"runId"`
+}
+
+// Start starts onboarding for req.Merchant. Like the payment-event consumer,
+// it never starts a second onboarding for a merchant, even once the first
+// has finished.
+func (s *Service) Start(ctx context.Context, req shared.OnboardingRequest) (Execution, error) {
+	if err := req.Validate(time.Now()); err != nil {
+		return Execution{}, fmt.Errorf("%w: %v", ErrInvalidRequest, err)
+	}
+	req.ActivityTaskQueue = s.ActivityTaskQueue
+
+	opts := client.StartWorkflowOptions{
+		ID:                                       shared.OnboardingWorkflowID(req.Merchant.MerchantID),
+		TaskQueue:                                s.TaskQueue,
+		WorkflowIDReusePolicy:                    enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+		WorkflowExecutionErrorWhenAlreadyStarted: true,
+	}
+	run, err := s.Client.ExecuteWorkflow(ctx, opts, workflows.OnboardingWorkflow, req)
+	if err != nil {
+		return Execution{}, mapError(err)
+	}
+	return Execution{WorkflowID: run.GetID(), RunID: run.GetRunID()}, nil
+}
+
+// SubmitDocument delivers the merchant's identity document ID.
+func (s *Service) SubmitDocument(ctx context.Context, merchantID, documentID string) error {
+	if documentID == "" {
+		return fmt.Errorf("%w: document ID is required", ErrInvalidRequest)
+	}
+	err := s.Client.SignalWorkflow(ctx, shared.OnboardingWorkflowID(merchantID), "", shared.SignalDocumentSubmitted, documentID)
+	return mapError(err)
+}
+
+// Status queries the onboarding's current status. It also works for
+// finished onboardings.
+func (s *Service) Status(ctx context.Context, merchantID string) (shared.OnboardingStatusResponse, error) {
+	var status shared.OnboardingStatusResponse
+	resp, err := s.Client.QueryWorkflow(ctx, shared.OnboardingWorkflowID(merchantID), "", shared.QueryOnboardingStatus)
+	if err != nil {
+		return status, mapError(err)
+	}
+	if err := resp.Get(&status); err != nil {
+		return status, fmt.Errorf("decode status: %w", err)
+	}
+	return status, nil
+}
+
+// ExtendDeadline gives the merchant more time to submit documents. The
+// workflow ignores extensions once documents are in or the deadline passed.
+func (s *Service) ExtendDeadline(ctx context.Context, merchantID string, ext shared.DeadlineExtension) error {
+	if ext.Days <= 0 {
+		return fmt.Errorf("%w: extension must be at least one day", ErrInvalidRequest)
+	}
+	err := s.Client.SignalWorkflow(ctx, shared.OnboardingWorkflowID(merchantID), "", shared.SignalExtendDeadline, ext)
+	return mapError(err)
+}
+
+// ResendReminder asks the workflow to send the merchant an extra reminder
+// now. The workflow ignores it unless documents are still outstanding.
+func (s *Service) ResendReminder(ctx context.Context, merchantID string, resend shared.ReminderResend) error {
+	err := s.Client.SignalWorkflow(ctx, shared.OnboardingWorkflowID(merchantID), "", shared.SignalResendReminder, resend)
+	return mapError(err)
+}
+
+// Cancel requests cancellation of the onboarding. The workflow stops without
+// disabling payments or running KYC.
+func (s *Service) Cancel(ctx context.Context, merchantID string) error {
+	return mapError(s.Client.CancelWorkflow(ctx, shared.OnboardingWorkflowID(merchantID), ""))
+}
+
+// Result returns the onboarding's result. Unless wait is set it returns
+// ErrNotCompleted instead of blocking while the workflow is still running.
+func (s *Service) Result(ctx context.Context, merchantID string, wait bool) (shared.OnboardingResult, error) {
+	var result shared.OnboardingResult
+	workflowID := shared.OnboardingWorkflowID(merchantID)
+
+	if !wait {
+		desc, err := s.Client.DescribeWorkflowExecution(ctx, workflowID, "")
+		if err != nil {
+			return result, mapError(err)
+		}
+		if desc.GetWorkflowExecutionInfo().GetStatus() == enums.WORKFLOW_EXECUTION_STATUS_RUNNING {
+			return result, ErrNotCompleted
+		}
+	}
+
+	if err := s.Client.GetWorkflow(ctx, workflowID, "").Get(ctx, &result); err != nil {
+		return result, mapError(err)
+	}
+	return result, nil
+}
+
+// ListFilter narrows List. Filters are combined with AND.
+type ListFilter struct {
+	// Status is an execution status such as "Running" or "Completed".
+	Status string
+	// OnboardingStatus is an onboarding status such as
+	// "AWAITING_KYC_DOCUMENTS", case-insensitive.
+	OnboardingStatus string
+	// Country is an ISO 3166 alpha-2 code.
+	Country      string
+	BusinessType string
+	// DeadlineWithin keeps onboardings whose deadline is less than this far
+	// away, including those already past it. Zero disables the filter.
+	DeadlineWithin time.Duration
+	// Where is an extra visibility query clause, e.g.
+	// "KYCAttempts > 1", ANDed with the other filters.
+	Where string
+	// Limit caps the number of results; 0 means 100.
+	Limit int
+}
+
+// Summary describes one onboarding in a List result. The onboarding fields
+// come from search attributes and are empty for executions that started
+// before they were indexed.
+type Summary struct {
+	MerchantID string     `json:"merchantId"`
+	WorkflowID string     `json:"workflowId"`
+	RunID      string     `json:"runId"`
+	Status     string     `json:"status"`
+	StartTime  time.Time  `json:"startTime"`
+	CloseTime  *time.Time `json:"closeTime,omitempty"`
+
+	OnboardingStatus shared.OnboardingStatus `json:"onboardingStatus,omitempty"`
+	Country          string                  `json:"country,omitempty"`
+	BusinessType     string                  `json:"businessType,omitempty"`
+	Deadline         *time.Time              `json:"deadline,omitempty"`
+	KYCAttempts      int                     `json:"kycAttempts"`
+}
+
+// Query builds the visibility query for the filter. now is the current
+// time, against which DeadlineWithin is measured.
+func (f ListFilter) Query(now time.Time) (string, error) {
+	clauses := []string{"WorkflowType = 'OnboardingWorkflow'"}
+	if f.Status != "" {
+		status, ok := parseStatus(f.Status)
+		if !ok {
+			return "", fmt.Errorf("%w: unknown status %q", ErrInvalidRequest, f.Status)
+		}
+		clauses = append(clauses, fmt.Sprintf("ExecutionStatus = '%s'", status))
+	}
+	if f.OnboardingStatus != "" {
+		status, ok := shared.ParseOnboardingStatus(f.OnboardingStatus)
+		if !ok {
+			return "", fmt.Errorf("%w: unknown onboarding status %q", ErrInvalidRequest, f.OnboardingStatus)
+		}
+		clauses = append(clauses, fmt.Sprintf("%s = '%s'", shared.SearchAttrOnboardingStatus.GetName(), status))
+	}
+	for _, kv := range []struct {
+		key   string
+		value string
+	}{
+		{shared.SearchAttrCountry.GetName(), strings.ToUpper(f.Country)},
+		{shared.SearchAttrBusinessType.GetName(), f.BusinessType},
+	} {
+		if kv.value == "" {
+			continue
+		}
+		// Values are quoted into the query, so keep them to plain words.
+		if strings.ContainsAny(kv.value, `'"\`) {
+			return "", fmt.Errorf("%w: invalid %s %q", ErrInvalidRequest, kv.key, kv.value)
+		}
+		clauses = append(clauses, fmt.Sprintf("%s = '%s'", kv.key, kv.value))
+	}
+	if f.DeadlineWithin < 0 {
+		return "", fmt.Errorf("%w: negative deadline window", ErrInvalidRequest)
+	}
+	if f.DeadlineWithin > 0 {
+		clauses = append(clauses, fmt.Sprintf("%s < '%s'",
+			shared.SearchAttrDeadline.GetName(), now.Add(f.DeadlineWithin).UTC().Format(time.RFC3339)))
+	}
+	if strings.TrimSpace(f.Where) != "" {
+		clauses = append(clauses, "("+f.Where+")")
+	}
+	return strings.Join(clauses, " AND "), nil
+}
+
+// maxPageSize caps the page size List requests from the visibility store.
+const maxPageSize = 1000
+
+// List returns onboardings from the visibility store, newest first.
+func (s *Service) List(ctx context.Context, filter ListFilter) ([]Summary, error) {
+	query, err := filter.Query(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var summaries []Summary
+	var pageToken []byte
+	for {
+		resp, err := s.Client.ListWorkflow(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Query:         query,
+			PageSize:      int32(min(limit, maxPageSize)),
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, mapError(err)
+		}
+		for _, info := range resp.GetExecutions() {
+			summaries = append(summaries, summarize(info))
+			if len(summaries) == limit {
+				return summaries, nil
+			}
+		}
+		pageToken = resp.GetNextPageToken()
+		if len(pageToken) == 0 {
+			return summaries, nil
+		}
+	}
+}
+
+// summarize builds a Summary from an execution and its search attributes.
+func summarize(info *workflowpb.WorkflowExecutionInfo) Summary {
+	summary := Summary{
+		MerchantID: strings.TrimPrefix(info.GetExecution().GetWorkflowId(), shared.OnboardingWorkflowID("")),
+		WorkflowID: info.GetExecution().GetWorkflowId(),
+		RunID:      info.GetExecution().GetRunId(),
+		Status:     info.GetStatus().String(),
+		StartTime:  info.GetStartTime().AsTime(),
+	}
+	if info.GetCloseTime() != nil {
+		closeTime := info.GetCloseTime().AsTime()
+		summary.CloseTime = &closeTime
+	}
+
+	// Undecodable attributes are left empty: a listing shouldn't fail
+	// because one execution has an odd value.
+	fields := info.GetSearchAttributes().GetIndexedFields()
+	dc := converter.GetDefaultDataConverter()
+	decode := func(key temporal.SearchAttributeKey, v interface{}) bool {
+		p, ok := fields[key.GetName()]
+		return ok && dc.FromPayload(p, v) == nil
+	}
+	var status string
+	if decode(shared.SearchAttrOnboardingStatus, &status) {
+		summary.OnboardingStatus = shared.OnboardingStatus(status)
+	}
+	decode(shared.SearchAttrCountry, &summary.Country)
+	decode(shared.SearchAttrBusinessType, &summary.BusinessType)
+	var deadline time.Time
+	if decode(shared.SearchAttrDeadline, &deadline) {
+		summary.Deadline = &deadline
+	}
+	var attempts int64
+	if decode(shared.SearchAttrKYCAttempts, &attempts) {
+		summary.KYCAttempts = int(attempts)
+	}
+	return summary
+}
+
+// parseStatus parses an execution status name such as "Running",
+// case-insensitively.
+func parseStatus(name string) (enums.WorkflowExecutionStatus, bool) {
+	for v := range enums.WorkflowExecutionStatus_name {
+		status := enums.WorkflowExecutionStatus(v)
+		if status != enums.WORKFLOW_EXECUTION_STATUS_UNSPECIFIED && strings.EqualFold(status.String(), name) {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// mapError translates Temporal errors into the package's errors.
+func mapError(err error) error {
+	var notFound *serviceerror.NotFound
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &notFound):
+		return fmt.Errorf("%w: %v", ErrNotFound, err)
+	case errors.As(err, &alreadyStarted):
+		return fmt.Errorf("%w: %v", ErrAlreadyStarted, err)
+	case temporal.IsCanceledError(err):
+		return fmt.Errorf("%w: %v", ErrCancelled, err)
+	default:
+		return err
+	}
+}
@@ -0,0 +1,148 @@
+package paymentevents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// SignalWithStarter is the subset of client.Client used by the Consumer.
+type SignalWithStarter interface {
+	SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+		options client.StartWorkflowOptions, workflow interface{}, workflowArgs ...interface{}) (client.WorkflowRun, error)
+}
+
+// Consumer turns payment events into onboarding workflows. Each event is
+// delivered with SignalWithStart on the merchant's workflow ID, so the first
+// payment starts OnboardingWorkflow and duplicate or concurrent events for
+// the same merchant are collapsed into that one execution.
+type Consumer struct {
+	Client    SignalWithStarter
+	TaskQueue string
+	// ActivityTaskQueue is passed to the workflows it starts in
+	// OnboardingRequest.ActivityTaskQueue.
+	ActivityTaskQueue string
+	// InitialBackoff and MaxBackoff bound the exponential backoff between
+	// attempts to deliver an event that failed to deliver.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// NewConsumer returns a Consumer starting workflows on the default task queues.
+func NewConsumer(c SignalWithStarter) *Consumer {
+	return &Consumer{
+		Client:            c,
+		TaskQueue:         shared.OnboardingWorkflowTaskQueue,
+		ActivityTaskQueue: shared.ActivityTaskQueue,
+		InitialBackoff:    time.Second,
+		MaxBackoff:        time.Minute,
+	}
+}
+
+// ErrAlreadyOnboarded is returned by Handle when the merchant's onboarding
+// has already finished. Payments after onboarding need no action.
+var ErrAlreadyOnboarded = errors.New("merchant onboarding already completed")
+
+// ErrInvalidEvent is returned by Handle for an event that can never be
+// delivered.
+var ErrInvalidEvent = errors.New("invalid payment event")
+
+// Handle delivers one event.
+func (c *Consumer) Handle(ctx context.Context, event shared.PaymentEvent) error {
+	if event.Merchant.MerchantID == "" {
+		return fmt.Errorf("%w: payment event %s has no merchant ID", ErrInvalidEvent, event.EventID)
+	}
+
+	workflowID := shared.OnboardingWorkflowID(event.Merchant.MerchantID)
+	opts := client.StartWorkflowOptions{
+		ID:        workflowID,
+		TaskQueue: c.TaskQueue,
+		// A merchant is onboarded once. Once the workflow has closed, later
+		// payments must not start a fresh 90-day onboarding.
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE,
+	}
+	req := shared.OnboardingRequest{Merchant: event.Merchant, ActivityTaskQueue: c.ActivityTaskQueue}
+
+	_, err := c.Client.SignalWithStartWorkflow(ctx, workflowID, shared.SignalPaymentReceived, event,
+		opts, workflows.OnboardingWorkflow, req)
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) {
+		return ErrAlreadyOnboarded
+	}
+	return err
+}
+
+// Run reads events from src until it is exhausted or ctx is cancelled.
+// Each event is retried with backoff until it is delivered, and only then
+// acked to src, so events are delivered in order and never skipped. Events
+// that can never be delivered are logged and acked.
+func (c *Consumer) Run(ctx context.Context, src Source) error {
+	for {
+		event, err := src.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			log.Printf("Skipping unreadable payment event: %v", err)
+			src.Ack(nil)
+			continue
+		}
+
+		switch err := c.deliver(ctx, event); {
+		case errors.Is(err, ErrAlreadyOnboarded):
+			log.Printf("Payment %s: merchant %s already onboarded", event.EventID, event.Merchant.MerchantID)
+		case errors.Is(err, ErrInvalidEvent):
+			log.Printf("Payment %s: dropping undeliverable event: %v", event.EventID, err)
+		case err != nil:
+			// Only cancellation stops delivery; the event stays unacked.
+			src.Ack(err)
+			return err
+		default:
+			log.Printf("Payment %s: delivered to %s", event.EventID, shared.OnboardingWorkflowID(event.Merchant.MerchantID))
+		}
+		src.Ack(nil)
+	}
+}
+
+// deliver calls Handle until the event is delivered, can never be, or ctx
+// is cancelled.
+func (c *Consumer) deliver(ctx context.Context, event shared.PaymentEvent) error {
+	backoff := c.InitialBackoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	for {
+		err := c.Handle(ctx, event)
+		var invalidArgument *serviceerror.InvalidArgument
+		switch {
+		case err == nil, errors.Is(err, ErrAlreadyOnboarded), errors.Is(err, ErrInvalidEvent):
+			return err
+		case errors.As(err, &invalidArgument):
+			return fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+		}
+
+		log.Printf("Payment %s: failed to deliver to onboarding workflow, retrying in %s: %v", event.EventID, backoff, err)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if c.MaxBackoff > 0 {
+			backoff = min(backoff, c.MaxBackoff)
+		}
+	}
+}
@@ -0,0 +1,297 @@
+// Package paymentevents feeds payment events from a pluggable source into
+// the merchant's OnboardingWorkflow.
+package paymentevents
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// Source yields payment events one at a time.
+type Source interface {
+	// Next blocks until the next event is available. It returns io.EOF when
+	// a finite source is exhausted, or ctx.Err() when ctx is cancelled.
+	Next(ctx context.Context) (shared.PaymentEvent, error)
+	// Ack reports the outcome of whatever Next returned last: nil once the
+	// event was delivered or deliberately dropped, otherwise the error that
+	// stopped its delivery. Sources only release or checkpoint an event
+	// acked with nil.
+	Ack(err error)
+}
+
+// MemorySource is an in-memory queue of events, used in tests.
+type MemorySource struct {
+	mu     sync.Mutex
+	events []shared.PaymentEvent
+	closed bool
+	ready  chan struct{}
+}
+
+// NewMemorySource returns a MemorySource pre-loaded with events.
+func NewMemorySource(events ...shared.PaymentEvent) *MemorySource {
+	return &MemorySource{events: events, ready: make(chan struct{}, 1)}
+}
+
+// Push enqueues an event.
+func (s *MemorySource) Push(event shared.PaymentEvent) {
+	s.mu.Lock()
+	s.events = append(s.events, event)
+	s.mu.Unlock()
+	s.notify()
+}
+
+// Close marks the source as finished; Next returns io.EOF once drained.
+func (s *MemorySource) Close() {
+	s.mu.Lock()
+	s.closed = true
+	s.mu.Unlock()
+	s.notify()
+}
+
+func (s *MemorySource) notify() {
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ack implements Source. Events are removed from a MemorySource by Next.
+func (s *MemorySource) Ack(error) {}
+
+// Next implements Source.
+func (s *MemorySource) Next(ctx context.Context) (shared.PaymentEvent, error) {
+	for {
+		s.mu.Lock()
+		if len(s.events) > 0 {
+			event := s.events[0]
+			s.events = s.events[1:]
+			s.mu.Unlock()
+			return event, nil
+		}
+		closed := s.closed
+		s.mu.Unlock()
+		if closed {
+			return shared.PaymentEvent{}, io.EOF
+		}
+
+		select {
+		case <-s.ready:
+		case <-ctx.Done():
+			return shared.PaymentEvent{}, ctx.Err()
+		}
+	}
+}
+
+// FileSource reads events from a JSON Lines file. With Follow set it keeps
+// polling for appended lines like `tail -f`; otherwise it returns io.EOF at
+// the end of the file.
+//
+// With a checkpoint file, the offset past the last acked line is saved there
+// and a reopened FileSource resumes from it, so an event that was read but
+// never delivered is read again after a restart.
+type FileSource struct {
+	Follow       bool
+	PollInterval time.Duration
+
+	file       *os.File
+	reader     *bufio.Reader
+	line       []byte // Partial line read before EOF while following.
+	checkpoint string
+	read       int64 // Offset past the last line read.
+	returned   int64 // Offset past the line Next returned last.
+}
+
+// OpenFileSource opens path for reading. Without a checkpoint path it reads
+// from the beginning; otherwise it resumes after the last acked line
+// recorded in checkpoint.
+func OpenFileSource(path string, follow bool, checkpoint string) (*FileSource, error) {
+	offset, err := readCheckpoint(checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open payment events file: %w", err)
+	}
+	if info, err := f.Stat(); err == nil && offset > info.Size() {
+		// The file was replaced by a shorter one; start over.
+		offset = 0
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to seek payment events file: %w", err)
+	}
+	return &FileSource{
+		Follow:       follow,
+		PollInterval: time.Second,
+		file:         f,
+		reader:       bufio.NewReader(f),
+		checkpoint:   checkpoint,
+		read:         offset,
+		returned:     offset,
+	}, nil
+}
+
+func readCheckpoint(path string) (int64, error) {
+	if path == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read payment events checkpoint: %w", err)
+	}
+	offset, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid payment events checkpoint %q", data)
+	}
+	return offset, nil
+}
+
+// Ack implements Source. Acking nil saves the checkpoint.
+func (s *FileSource) Ack(err error) {
+	if err != nil || s.checkpoint == "" {
+		return
+	}
+	// Written to a temporary file and renamed, so a crash never leaves a
+	// torn checkpoint behind.
+	tmp := s.checkpoint + ".tmp"
+	if werr := os.WriteFile(tmp, []byte(strconv.FormatInt(s.returned, 10)+"\n"), 0o644); werr != nil {
+		log.Printf("Failed to save payment events checkpoint: %v", werr)
+		return
+	}
+	if rerr := os.Rename(tmp, s.checkpoint); rerr != nil {
+		log.Printf("Failed to save payment events checkpoint: %v", rerr)
+	}
+}
+
+// Next implements Source. Malformed lines are returned as errors so the
+// caller can decide whether to skip them.
+func (s *FileSource) Next(ctx context.Context) (shared.PaymentEvent, error) {
+	for {
+		chunk, err := s.reader.ReadBytes('\n')
+		s.line = append(s.line, chunk...)
+		s.read += int64(len(chunk))
+
+		if errors.Is(err, io.EOF) && s.Follow {
+			// Keep the partial line until the writer finishes it.
+			select {
+			case <-time.After(s.PollInterval):
+				continue
+			case <-ctx.Done():
+				return shared.PaymentEvent{}, ctx.Err()
+			}
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return shared.PaymentEvent{}, err
+		}
+
+		line := bytes.TrimSpace(s.line)
+		s.line = nil
+		s.returned = s.read
+		if len(line) > 0 {
+			var event shared.PaymentEvent
+			if err := json.Unmarshal(line, &event); err != nil {
+				return shared.PaymentEvent{}, fmt.Errorf("invalid payment event %q: %w", line, err)
+			}
+			return event, nil
+		}
+		if err != nil {
+			return shared.PaymentEvent{}, io.EOF
+		}
+	}
+}
+
+// Close closes the underlying file.
+func (s *FileSource) Close() error {
+	return s.file.Close()
+}
+
+// HTTPSource accepts events POSTed as JSON to its handler and queues them
+// for Next. The request is held until the event is acked: a 204 response
+// means the event was delivered to its onboarding workflow, a 503 that it
+// wasn't and the sender must retry.
+type HTTPSource struct {
+	events  chan httpEvent
+	current chan error // Result channel of the event Next returned last.
+}
+
+type httpEvent struct {
+	event shared.PaymentEvent
+	done  chan error
+}
+
+// NewHTTPSource returns an HTTPSource buffering up to buffer events. When
+// the buffer is full, the handler responds 503 so the sender retries.
+func NewHTTPSource(buffer int) *HTTPSource {
+	return &HTTPSource{events: make(chan httpEvent, buffer)}
+}
+
+// ServeHTTP implements http.Handler.
+func (s *HTTPSource) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var event shared.PaymentEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		http.Error(rw, fmt.Sprintf("invalid payment event: %v", err), http.StatusBadRequest)
+		return
+	}
+	queued := httpEvent{event: event, done: make(chan error, 1)}
+	select {
+	case s.events <- queued:
+	default:
+		http.Error(rw, "event queue full", http.StatusServiceUnavailable)
+		return
+	}
+
+	select {
+	case err := <-queued.done:
+		if err != nil {
+			http.Error(rw, fmt.Sprintf("payment event not delivered: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		rw.WriteHeader(http.StatusNoContent)
+	case <-r.Context().Done():
+		// The sender gave up; it retries, and the workflow drops the
+		// duplicate by event ID if this delivery still succeeds.
+	}
+}
+
+// Next implements Source.
+func (s *HTTPSource) Next(ctx context.Context) (shared.PaymentEvent, error) {
+	select {
+	case queued := <-s.events:
+		s.current = queued.done
+		return queued.event, nil
+	case <-ctx.Done():
+		return shared.PaymentEvent{}, ctx.Err()
+	}
+}
+
+// Ack implements Source, answering the request that posted the event.
+func (s *HTTPSource) Ack(err error) {
+	if s.current != nil {
+		s.current <- err
+		s.current = nil
+	}
+}
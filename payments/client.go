@@ -0,0 +1,198 @@
+// Package payments talks to the payment platform that processes merchant
+// transactions: an HTTP client for the real API and an in-memory fake of it
+// for local development and tests.
+package payments
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrMerchantNotFound is returned when the platform has no such merchant.
+// Retrying cannot fix it.
+var ErrMerchantNotFound = errors.New("merchant not found")
+
+// APIError is a non-2xx response from the payment platform.
+type APIError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("payments API returned %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the request may succeed if sent again:
+// rate limiting (429), server-side failures (5xx) and a 404 for anything but
+// an unknown merchant, which means the request didn't reach the merchant API
+// (a misconfigured base URL, or a proxy or rollout in the way) and can
+// succeed once that is fixed.
+func (e *APIError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusNotFound || e.StatusCode >= 500
+}
+
+// Config configures the HTTP client.
+type Config struct {
+	BaseURL string        // e.g. "https://payments.internal.example.com"
+	Token   string        // Sent as a bearer token.
+	Timeout time.Duration // Per-request timeout. Defaults to 10s.
+
+	// Transport sends the requests; defaults to http.DefaultTransport. Wrap
+	// it to trace or instrument calls.
+	Transport http.RoundTripper
+}
+
+// Client is an HTTP client for the payment platform API.
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// NewClient returns a Client for cfg.
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.BaseURL == "" {
+		return nil, errors.New("payments: base URL is required")
+	}
+	if _, err := url.Parse(cfg.BaseURL); err != nil {
+		return nil, fmt.Errorf("payments: invalid base URL: %w", err)
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(cfg.BaseURL, "/"),
+		token:      cfg.Token,
+		httpClient: &http.Client{Timeout: timeout, Transport: cfg.Transport},
+	}, nil
+}
+
+// DisablePayments stops all payment processing for the merchant.
+func (c *Client) DisablePayments(ctx context.Context, merchantID string) error {
+	return c.do(ctx, http.MethodPost, merchantPath(merchantID, "disable-payments"), nil, nil)
+}
+
+// HoldPayouts keeps accepting payments but stops paying out to the merchant.
+func (c *Client) HoldPayouts(ctx context.Context, merchantID string) error {
+	return c.do(ctx, http.MethodPost, merchantPath(merchantID, "hold-payouts"), nil, nil)
+}
+
+// CapTransactionVolume limits the merchant's daily processed volume.
+// dailyCap is in minor units of the settlement currency.
+func (c *Client) CapTransactionVolume(ctx context.Context, merchantID string, dailyCap int64) error {
+	body := volumeCapBody{DailyVolumeCap: dailyCap}
+	return c.do(ctx, http.MethodPut, merchantPath(merchantID, "volume-cap"), body, nil)
+}
+
+// LiftRestrictions removes payout holds and volume caps and re-enables
+// payment processing.
+func (c *Client) LiftRestrictions(ctx context.Context, merchantID string) error {
+	return c.do(ctx, http.MethodPost, merchantPath(merchantID, "lift-restrictions"), nil, nil)
+}
+
+type volumeCapBody struct {
+	DailyVolumeCap int64 `json:"dailyVolumeCap"`
+}
+
+// Conditions are standing processing conditions attached to a merchant's
+// approval. Unlike onboarding restrictions they are not removed by
+// LiftRestrictions.
+type Conditions struct {
+	MonthlyVolumeLimit       int64 `json:"monthlyVolumeLimit,omitempty"` // Minor units; 0 means no limit.
+	ThirdPartyPayoutsBlocked bool  `json:"thirdPartyPayoutsBlocked,omitempty"`
+	EnhancedMonitoring       bool  `json:"enhancedMonitoring,omitempty"`
+}
+
+// ApplyConditions replaces the merchant's processing conditions.
+func (c *Client) ApplyConditions(ctx context.Context, merchantID string, conditions Conditions) error {
+	return c.do(ctx, http.MethodPut, merchantPath(merchantID, "conditions"), conditions, nil)
+}
+
+// GetMerchant returns the platform's view of the merchant.
+func (c *Client) GetMerchant(ctx context.Context, merchantID string) (Merchant, error) {
+	var m Merchant
+	err := c.do(ctx, http.MethodGet, merchantPath(merchantID, ""), nil, &m)
+	return m, err
+}
+
+// Merchant is the payment platform's state for a merchant.
+type Merchant struct {
+	MerchantID      string `json:"merchantId"`
+	PaymentsEnabled bool   `json:"paymentsEnabled"`
+	PayoutsHeld     bool   `json:"payoutsHeld"`
+	DailyVolumeCap  int64  `json:"dailyVolumeCap,omitempty"` // 0 means uncapped.
+
+	Conditions Conditions `json:"conditions"`
+}
+
+func merchantPath(merchantID, action string) string {
+	p := "/v1/merchants/" + url.PathEscape(merchantID)
+	if action != "" {
+		p += "/" + action
+	}
+	return p
+}
+
+// errorBody is the platform's error response.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Network errors and timeouts are transient.
+		return fmt.Errorf("payments API request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var eb errorBody
+		_ = json.NewDecoder(resp.Body).Decode(&eb)
+		// Only the merchant API says which merchant is unknown; a bare 404
+		// may come from anything between us and it.
+		if resp.StatusCode == http.StatusNotFound && eb.Error == ErrMerchantNotFound.Error() {
+			return ErrMerchantNotFound
+		}
+		if eb.Error == "" {
+			eb.Error = http.StatusText(resp.StatusCode)
+		}
+		return &APIError{StatusCode: resp.StatusCode, Message: eb.Error}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode payments API response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package payments
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// FakeServer is an in-memory stand-in for the payment platform API. It
+// records merchant state so tests can assert what actually happened, and can
+// be told to fail upcoming requests to exercise retry handling.
+type FakeServer struct {
+	// Token, when set, is required as a bearer token on every request.
+	Token string
+
+	mu        sync.Mutex
+	merchants map[string]*Merchant
+	failures  []int // Status codes returned by the next requests, in order.
+}
+
+// NewFakeServer returns a FakeServer knowing the given merchants, all with
+// payments enabled.
+func NewFakeServer(merchantIDs ...string) *FakeServer {
+	f := &FakeServer{merchants: make(map[string]*Merchant)}
+	for _, id := range merchantIDs {
+		f.AddMerchant(id)
+	}
+	return f
+}
+
+// AddMerchant registers a merchant with payments enabled.
+func (f *FakeServer) AddMerchant(merchantID string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.merchants[merchantID] = &Merchant{MerchantID: merchantID, PaymentsEnabled: true}
+}
+
+// Merchant returns a copy of the recorded state for merchantID.
+func (f *FakeServer) Merchant(merchantID string) (Merchant, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m, ok := f.merchants[merchantID]
+	if !ok {
+		return Merchant{}, false
+	}
+	return *m, true
+}
+
+// FailNext makes the next len(statusCodes) requests fail with the given
+// status codes, in order.
+func (f *FakeServer) FailNext(statusCodes ...int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = append(f.failures, statusCodes...)
+}
+
+// ServeHTTP implements http.Handler.
+func (f *FakeServer) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.Token != "" && r.Header.Get("Authorization") != "Bearer "+f.Token {
+		writeError(rw, http.StatusUnauthorized, "invalid token")
+		return
+	}
+	if len(f.failures) > 0 {
+		status := f.failures[0]
+		f.failures = f.failures[1:]
+		writeError(rw, status, "injected failure")
+		return
+	}
+
+	// Paths: /v1/merchants/{id}[/{action}]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/v1/merchants/"), "/")
+	if !strings.HasPrefix(r.URL.Path, "/v1/merchants/") || parts[0] == "" || len(parts) > 2 {
+		writeError(rw, http.StatusNotFound, "no such endpoint")
+		return
+	}
+	m, ok := f.merchants[parts[0]]
+	if !ok {
+		writeError(rw, http.StatusNotFound, ErrMerchantNotFound.Error())
+		return
+	}
+	action := ""
+	if len(parts) == 2 {
+		action = parts[1]
+	}
+
+	switch {
+	case r.Method == http.MethodGet && action == "":
+	case r.Method == http.MethodPost && action == "disable-payments":
+		m.PaymentsEnabled = false
+	case r.Method == http.MethodPost && action == "hold-payouts":
+		m.PayoutsHeld = true
+	case r.Method == http.MethodPut && action == "volume-cap":
+		var body volumeCapBody
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.DailyVolumeCap <= 0 {
+			writeError(rw, http.StatusBadRequest, "dailyVolumeCap must be positive")
+			return
+		}
+		m.DailyVolumeCap = body.DailyVolumeCap
+	case r.Method == http.MethodPut && action == "conditions":
+		var body Conditions
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.MonthlyVolumeLimit < 0 {
+			writeError(rw, http.StatusBadRequest, "invalid conditions")
+			return
+		}
+		m.Conditions = body
+	case r.Method == http.MethodPost && action == "lift-restrictions":
+		m.PaymentsEnabled = true
+		m.PayoutsHeld = false
+		m.DailyVolumeCap = 0
+	default:
+		writeError(rw, http.StatusMethodNotAllowed, "unsupported operation")
+		return
+	}
+	writeJSON(rw, m)
+}
+
+func writeJSON(rw http.ResponseWriter, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(rw).Encode(v)
+}
+
+func writeError(rw http.ResponseWriter, status int, msg string) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_ = json.NewEncoder(rw).Encode(errorBody{Error: msg})
+}
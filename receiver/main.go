@@ -0,0 +1,39 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/webhooks"
+)
+
+func main() {
+	c, _, err := config.Dial(nil)
+	if err != nil {
+		log.Fatalf("Unable to create Temporal client: %v", err)
+	}
+	defer c.Close()
+
+	addr := os.Getenv("WEBHOOK_ADDR")
+	if addr == "" {
+		addr = ":8090"
+	}
+
+	// The signing secret configured for the provider's event webhook; every
+	// receipt must be signed with it.
+	secret := os.Getenv("RECEIPT_WEBHOOK_SECRET")
+	if secret == "" {
+		log.Fatalf("RECEIPT_WEBHOOK_SECRET is required")
+	}
+
+	// Point the notification provider's event webhook at this endpoint.
+	mux := http.NewServeMux()
+	mux.Handle("/webhooks/delivery", webhooks.NewReceiptHandler(c, secret))
+
+	log.Printf("Listening for delivery receipts on %s/webhooks/delivery", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		log.Fatalf("Webhook receiver stopped: %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/operatorservice/v1"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/shared"
+)
+
+// Registers the onboarding search attributes on the namespace. Safe to run
+// repeatedly: attributes that already exist are left alone.
+func main() {
+	c, cfg, err := config.Dial(nil)
+	if err != nil {
+		log.Fatalf("Unable to create Temporal client: %v", err)
+	}
+	defer c.Close()
+
+	ctx := context.Background()
+	namespace := cfg.Temporal.Namespace
+	existing, err := c.OperatorService().ListSearchAttributes(ctx, &operatorservice.ListSearchAttributesRequest{Namespace: namespace})
+	if err != nil {
+		log.Fatalf("Unable to list search attributes: %v", err)
+	}
+
+	missing := make(map[string]enums.IndexedValueType)
+	for name, typ := range shared.SearchAttributeTypes {
+		have, ok := existing.GetCustomAttributes()[name]
+		switch {
+		case !ok:
+			missing[name] = typ
+		case have != typ:
+			log.Fatalf("Search attribute %s is registered as %s, want %s", name, have, typ)
+		}
+	}
+	if len(missing) == 0 {
+		log.Printf("All search attributes are registered on namespace %q", namespace)
+		return
+	}
+
+	_, err = c.OperatorService().AddSearchAttributes(ctx, &operatorservice.AddSearchAttributesRequest{
+		Namespace:        namespace,
+		SearchAttributes: missing,
+	})
+	if err != nil {
+		log.Fatalf("Unable to register search attributes: %v", err)
+	}
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	log.Printf("Registered search attributes on namespace %q: %v", namespace, names)
+}
@@ -1,8 +1,13 @@
 package shared
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
-// Task queue names.
+// Default task queue names. Deployments can override them in the config
+// file or environment; see the config package.
 const (
 	OnboardingWorkflowTaskQueue = "onboarding-workflow-tq"
 	ActivityTaskQueue           = "activity-tq"
@@ -11,17 +16,125 @@ const (
 // Signal and query names.
 const (
 	SignalDocumentSubmitted = "signal-document-submitted"
+	SignalDeliveryReceipt   = "signal-delivery-receipt"
+	SignalPaymentReceived   = "signal-payment-received"
+	SignalExtendDeadline    = "signal-extend-deadline"
+	SignalResendReminder    = "signal-resend-reminder"
 	QueryOnboardingStatus   = "query-onboarding-status"
 )
 
+// OnboardingWorkflowID returns the business-meaningful workflow ID for a
+// merchant's onboarding. It doubles as an idempotency key: only one
+// onboarding can run per merchant at a time.
+func OnboardingWorkflowID(merchantID string) string {
+	return fmt.Sprintf("onboard-merchant-%s", merchantID)
+}
+
+// KYCWorkflowID returns the workflow ID of the merchant's identity
+// verification child workflow.
+func KYCWorkflowID(merchantID string) string {
+	return fmt.Sprintf("kyc-verify-%s", merchantID)
+}
+
+// MerchantIDFromWorkflowID returns the merchant ID in an onboarding or
+// identity verification workflow ID, or "" for any other workflow.
+func MerchantIDFromWorkflowID(workflowID string) string {
+	for _, prefix := range []string{OnboardingWorkflowID(""), KYCWorkflowID("")} {
+		if id, ok := strings.CutPrefix(workflowID, prefix); ok {
+			return id
+		}
+	}
+	return ""
+}
+
 // Compliance timeline constants.
 const (
-	ReminderDay30 = 30 * 24 * time.Hour
-	ReminderDay60 = 60 * 24 * time.Hour
-	DeadlineDay90 = 90 * 24 * time.Hour
+	ReminderDay30     = 30 * 24 * time.Hour
+	ReminderDay60     = 60 * 24 * time.Hour
+	FinalWarningDay83 = 83 * 24 * time.Hour
+	FinalWarningDay87 = 87 * 24 * time.Hour
+	FinalWarningDay89 = 89 * 24 * time.Hour
+	DeadlineDay90     = 90 * 24 * time.Hour
+)
+
+// Graduated restriction milestones for the standard risk tier.
+const (
+	PayoutHoldDay75 = 75 * 24 * time.Hour
+	VolumeCapDay85  = 85 * 24 * time.Hour
+)
+
+// Merchant risk tiers. The tier decides how early restrictions apply.
+const (
+	RiskTierLow      = "low"
+	RiskTierStandard = "standard"
+	RiskTierHigh     = "high"
 )
 
+// RestrictionSchedules lists, per risk tier, the restrictions applied while
+// documents are outstanding. Offsets are measured from workflow start. Full
+// disablement at DeadlineDay90 applies to every tier and is not listed.
+// Volume caps are daily limits in minor units of the settlement currency.
+var RestrictionSchedules = map[string][]RestrictionStep{
+	RiskTierLow: {
+		{At: VolumeCapDay85, Level: RestrictionPayoutHold},
+	},
+	RiskTierStandard: {
+		{At: PayoutHoldDay75, Level: RestrictionPayoutHold},
+		{At: VolumeCapDay85, Level: RestrictionVolumeCap, DailyVolumeCap: 100_000},
+	},
+	RiskTierHigh: {
+		{At: ReminderDay60, Level: RestrictionPayoutHold},
+		{At: PayoutHoldDay75, Level: RestrictionVolumeCap, DailyVolumeCap: 50_000},
+	},
+}
+
+// RestrictionSchedule returns the schedule for tier, falling back to the
+// standard tier for unknown or empty tiers.
+func RestrictionSchedule(tier string) []RestrictionStep {
+	if steps, ok := RestrictionSchedules[tier]; ok {
+		return steps
+	}
+	return RestrictionSchedules[RiskTierStandard]
+}
+
+// Reminder urgency levels. Urgent reminders announce a shortened deadline;
+// final reminders warn that payments will be disabled at the deadline.
+const (
+	UrgencyStandard = "standard"
+	UrgencyUrgent   = "urgent"
+	UrgencyFinal    = "final"
+)
+
+// ReminderSchedule lists every reminder sent while documents are
+// outstanding. Offsets are measured from workflow start, not from the
+// previous reminder, so a slow reminder never delays the next one.
+var ReminderSchedule = []ReminderStep{
+	{At: ReminderDay30, ReminderType: "day30", Urgency: UrgencyStandard},
+	{At: ReminderDay60, ReminderType: "day60", Urgency: UrgencyStandard},
+	{At: FinalWarningDay83, ReminderType: "day83", Urgency: UrgencyFinal},
+	{At: FinalWarningDay87, ReminderType: "day87", Urgency: UrgencyFinal},
+	{At: FinalWarningDay89, ReminderType: "day89", Urgency: UrgencyFinal},
+}
+
+// LegacyReminderSchedule is the schedule of executions started before
+// ReminderSchedule: the Day 30 and Day 60 reminders only, each waited for
+// after the previous one was sent.
+var LegacyReminderSchedule = []ReminderStep{
+	{At: ReminderDay30, ReminderType: "day30", Urgency: UrgencyStandard},
+	{At: ReminderDay60, ReminderType: "day60", Urgency: UrgencyStandard},
+}
+
 // Error types for non-retryable failures.
 const (
 	ErrTypeIdentityVerificationFailed = "IdentityVerificationFailed"
+	ErrTypeMerchantNotFound           = "MerchantNotFound"
+	ErrTypePaymentsRequestRejected    = "PaymentsRequestRejected"
+)
+
+// Reason codes reported in VerificationResult and OnboardingResult.
+const (
+	ReasonDocumentsNotSubmitted      = "DOCUMENTS_NOT_SUBMITTED"
+	ReasonVolumeThresholdExceeded    = "VOLUME_THRESHOLD_EXCEEDED"
+	ReasonSupplierRejected           = "SUPPLIER_REJECTED"
+	ReasonInternalVerificationFailed = "INTERNAL_VERIFICATION_FAILED"
 )
@@ -0,0 +1,33 @@
+package shared
+
+// Business metric names. The workflows and activities emit them through the
+// SDK metrics handler, so they carry its namespace, task_queue and
+// workflow_type (or activity_type) tags. With the Prometheus exporter,
+// counters gain a _total suffix and timers a _seconds one.
+const (
+	// MetricOnboardingStarted counts onboardings started. Tags: country.
+	MetricOnboardingStarted = "onboarding_started"
+	// MetricOnboardingCompleted counts finished onboardings. Tags: outcome
+	// (an Outcome, CANCELLED or FAILED) and country.
+	MetricOnboardingCompleted = "onboarding_completed"
+	// MetricDocumentSubmissionLatency is the time from first payment to the
+	// merchant submitting their identity document. Tags: country.
+	MetricDocumentSubmissionLatency = "onboarding_document_submission_latency"
+	// MetricKYCAttempts counts KYC verification attempts. Tags: result
+	// (passed, rejected or error) and country.
+	MetricKYCAttempts = "onboarding_kyc_attempts"
+	// MetricSupplierLatency is the verification supplier's response time.
+	MetricSupplierLatency = "kyc_supplier_latency"
+	// MetricSupplierRequests counts verification supplier calls. Tags:
+	// result (passed, conditional, rejected or error).
+	MetricSupplierRequests = "kyc_supplier_requests"
+	// MetricRemindersSent counts reminders delivered, not counting
+	// deduplicated retries. Tags: reminder_type and channel.
+	MetricRemindersSent = "onboarding_reminders_sent"
+)
+
+// Outcomes MetricOnboardingCompleted reports besides the Outcome values.
+const (
+	MetricOutcomeCancelled = "CANCELLED"
+	MetricOutcomeFailed    = "FAILED"
+)
@@ -1,21 +1,130 @@
 package shared
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
 // OnboardingStatus represents the current state of merchant onboarding.
 type OnboardingStatus string
 
 const (
-	StatusPending          OnboardingStatus = "PENDING"
-	StatusRemindersActive  OnboardingStatus = "AWAITING_KYC_DOCUMENTS"
-	StatusKYCInProgress    OnboardingStatus = "KYC_IN_PROGRESS"
-	StatusApproved         OnboardingStatus = "APPROVED"
-	StatusRejected         OnboardingStatus = "REJECTED"
-	StatusPaymentsDisabled OnboardingStatus = "PAYMENTS_DISABLED"
+	StatusPending                OnboardingStatus = "PENDING"
+	StatusRemindersActive        OnboardingStatus = "AWAITING_KYC_DOCUMENTS"
+	StatusKYCInProgress          OnboardingStatus = "KYC_IN_PROGRESS"
+	StatusApproved               OnboardingStatus = "APPROVED"
+	StatusApprovedWithConditions OnboardingStatus = "APPROVED_WITH_CONDITIONS"
+	StatusRejected               OnboardingStatus = "REJECTED"
+	StatusPaymentsDisabled       OnboardingStatus = "PAYMENTS_DISABLED"
+	StatusCancelled              OnboardingStatus = "CANCELLED"
+)
+
+// ParseOnboardingStatus parses a status name such as
+// "AWAITING_KYC_DOCUMENTS", ignoring case and accepting '-' for '_'.
+func ParseOnboardingStatus(name string) (OnboardingStatus, bool) {
+	name = strings.ReplaceAll(strings.ToUpper(name), "-", "_")
+	for _, s := range []OnboardingStatus{
+		StatusPending, StatusRemindersActive, StatusKYCInProgress, StatusApproved,
+		StatusApprovedWithConditions, StatusRejected, StatusPaymentsDisabled, StatusCancelled,
+	} {
+		if string(s) == name {
+			return s, true
+		}
+	}
+	return "", false
+}
+
+// Final reports whether the onboarding has ended with status s.
+func (s OnboardingStatus) Final() bool {
+	switch s {
+	case StatusApproved, StatusApprovedWithConditions, StatusRejected, StatusPaymentsDisabled, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// RestrictionLevel is how far a merchant's payment processing has been
+// restricted for missing KYC documents. Levels only escalate until lifted.
+type RestrictionLevel string
+
+const (
+	RestrictionNone       RestrictionLevel = "NONE"
+	RestrictionPayoutHold RestrictionLevel = "PAYOUT_HOLD"
+	RestrictionVolumeCap  RestrictionLevel = "VOLUME_CAP"
+	RestrictionDisabled   RestrictionLevel = "PAYMENTS_DISABLED"
 )
 
+// RestrictionStep is one entry in a RestrictionSchedules schedule.
+type RestrictionStep struct {
+	At             time.Duration // Offset from workflow start.
+	Level          RestrictionLevel
+	DailyVolumeCap int64 // For RestrictionVolumeCap only.
+}
+
+// VolumeCapRequest is the input to the CapTransactionVolume activity.
+type VolumeCapRequest struct {
+	MerchantID     string `json:"merchantId"`
+	DailyVolumeCap int64  `json:"dailyVolumeCap"` // Minor units of the settlement currency.
+}
+
 // OnboardingStatusResponse is returned by the query handler.
 type OnboardingStatusResponse struct {
-	Status        OnboardingStatus `json:"status"`
-	DaysRemaining int              `json:"daysRemaining"`
+	Status        OnboardingStatus  `json:"status"`
+	Deadline      time.Time         `json:"deadline"`
+	DaysRemaining int               `json:"daysRemaining"`
+	Restriction   RestrictionLevel  `json:"restriction"`
+	PaymentsSeen  int               `json:"paymentsSeen"`
+	Deliveries    []DeliveryReceipt `json:"deliveries,omitempty"`
+	Escalated     bool              `json:"escalated"`
+
+	// ProcessedVolume is the total processed so far, per currency, in minor units.
+	ProcessedVolume   map[string]int64 `json:"processedVolume,omitempty"`
+	EarlyKYCTriggered bool             `json:"earlyKycTriggered"`
+
+	// Conditions is set once the merchant is approved with conditions.
+	Conditions *MerchantConditions `json:"conditions,omitempty"`
+
+	// RunID and Sequence identify the latest StatusEvent, which describes
+	// this status; Sequence is 0 if none has been published yet.
+	RunID    string `json:"runId"`
+	Sequence int    `json:"sequence"`
+}
+
+// StatusEvent is published by the workflow whenever its status or payment
+// restriction changes. Sequence increases by one per event within a run, so
+// subscribers can drop stale or repeated events.
+type StatusEvent struct {
+	MerchantID  string           `json:"merchantId"`
+	WorkflowID  string           `json:"workflowId"`
+	RunID       string           `json:"runId"`
+	Sequence    int              `json:"sequence"`
+	Status      OnboardingStatus `json:"status"`
+	Restriction RestrictionLevel `json:"restriction"`
+	Deadline    time.Time        `json:"deadline"`
+	At          time.Time        `json:"at"`
+}
+
+// DeadlineExtension is the payload of SignalExtendDeadline. Operators use it
+// to give a merchant more time to submit documents.
+type DeadlineExtension struct {
+	Days   int    `json:"days"`
+	Reason string `json:"reason,omitempty"`
+
+	// RequestID, when set, makes the extension idempotent: the workflow
+	// applies each request ID once. Batch operations use the batch ID.
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// ReminderResend is the payload of SignalResendReminder: send the merchant
+// an extra reminder now, outside the regular schedule.
+type ReminderResend struct {
+	Reason string `json:"reason,omitempty"`
+
+	// RequestID makes the resend idempotent, as for DeadlineExtension.
+	RequestID string `json:"requestId,omitempty"`
 }
 
 // MerchantInfo contains the merchant's registration details.
@@ -25,18 +134,262 @@ type MerchantInfo struct {
 	Email        string `json:"email"`
 	Country      string `json:"country"`
 	BusinessType string `json:"businessType"`
+	// Phone is used as the fallback reminder channel when email hard-bounces.
+	Phone string `json:"phone,omitempty"`
+	// AccountManagerEmail is notified when the merchant cannot be reached.
+	AccountManagerEmail string `json:"accountManagerEmail,omitempty"`
+	// RiskTier selects the restriction schedule: RiskTierLow, RiskTierStandard
+	// (default) or RiskTierHigh.
+	RiskTier string `json:"riskTier,omitempty"`
+	// Timezone is an IANA zone name (e.g. "Europe/Amsterdam"). When empty,
+	// it is derived from Country.
+	Timezone string `json:"timezone,omitempty"`
+}
+
+// Validate checks the fields an onboarding depends on.
+func (m MerchantInfo) Validate() error {
+	switch {
+	case m.MerchantID == "":
+		return errors.New("merchantId is required")
+	case strings.ContainsAny(m.MerchantID, " \t/"):
+		return fmt.Errorf("merchantId %q contains whitespace or '/'", m.MerchantID)
+	case !strings.Contains(m.Email, "@"):
+		return fmt.Errorf("invalid email %q", m.Email)
+	case len(m.Country) != 2 || strings.ToUpper(m.Country) != m.Country:
+		return fmt.Errorf("country %q is not an ISO 3166 alpha-2 code", m.Country)
+	}
+	if _, ok := RestrictionSchedules[m.RiskTier]; m.RiskTier != "" && !ok {
+		return fmt.Errorf("unknown riskTier %q", m.RiskTier)
+	}
+	if m.Timezone != "" {
+		if _, err := time.LoadLocation(m.Timezone); err != nil {
+			return fmt.Errorf("unknown timezone %q", m.Timezone)
+		}
+	}
+	return nil
+}
+
+// SendWindow is the local-time window, in whole hours, during which
+// merchant-facing reminders may be delivered. EndHour is exclusive.
+type SendWindow struct {
+	StartHour int `json:"startHour"`
+	EndHour   int `json:"endHour"`
+}
+
+// DefaultSendWindow is used when OnboardingRequest.ReminderWindow is unset.
+var DefaultSendWindow = SendWindow{StartHour: 9, EndHour: 17}
+
+// Valid reports whether the window is a non-empty range within a day.
+func (sw SendWindow) Valid() bool {
+	return sw.StartHour >= 0 && sw.StartHour < sw.EndHour && sw.EndHour <= 24
 }
 
 // OnboardingRequest is the input to the OnboardingWorkflow.
 type OnboardingRequest struct {
 	Merchant MerchantInfo `json:"merchant"`
+	// ReminderWindow overrides DefaultSendWindow for this merchant.
+	ReminderWindow *SendWindow `json:"reminderWindow,omitempty"`
+	// VolumePolicy overrides DefaultVolumePolicy for this merchant.
+	VolumePolicy *VolumePolicy `json:"volumePolicy,omitempty"`
+
+	// FirstPaymentAt, for merchants migrated from another system, is when
+	// the merchant's first payment was received. The timeline and deadline
+	// run from it instead of from workflow start, and reminders that fell
+	// due before the workflow started are not sent.
+	FirstPaymentAt *time.Time `json:"firstPaymentAt,omitempty"`
+	// DocumentID, when set, is a document the merchant already submitted;
+	// the workflow goes straight to KYC.
+	DocumentID string `json:"documentId,omitempty"`
+
+	// ActivityTaskQueue is the task queue the workflow schedules activities
+	// on, filled in from configuration when the onboarding is started.
+	// Empty means ActivityTaskQueue, as for executions started before it
+	// was configurable.
+	ActivityTaskQueue string `json:"activityTaskQueue,omitempty"`
+}
+
+// ActivityQueue returns the activity task queue for the request.
+func (r OnboardingRequest) ActivityQueue() string {
+	if r.ActivityTaskQueue == "" {
+		return ActivityTaskQueue
+	}
+	return r.ActivityTaskQueue
+}
+
+// Validate checks the request before an onboarding is started. now is the
+// current time, against which FirstPaymentAt is checked.
+func (r OnboardingRequest) Validate(now time.Time) error {
+	if err := r.Merchant.Validate(); err != nil {
+		return err
+	}
+	if r.ReminderWindow != nil && !r.ReminderWindow.Valid() {
+		return fmt.Errorf("invalid reminderWindow %d-%d", r.ReminderWindow.StartHour, r.ReminderWindow.EndHour)
+	}
+	if r.VolumePolicy != nil {
+		if err := r.VolumePolicy.Validate(); err != nil {
+			return fmt.Errorf("invalid volumePolicy: %w", err)
+		}
+	}
+	if r.FirstPaymentAt != nil {
+		if r.FirstPaymentAt.After(now) {
+			return fmt.Errorf("firstPaymentAt %s is in the future", r.FirstPaymentAt.Format(time.RFC3339))
+		}
+		if !r.FirstPaymentAt.Add(DeadlineDay90).After(now) {
+			return fmt.Errorf("firstPaymentAt %s: the 90-day deadline has already passed", r.FirstPaymentAt.Format(time.RFC3339))
+		}
+	}
+	return nil
 }
 
+// VolumePolicy configures volume-triggered early KYC. Regulations require
+// KYC sooner once an unverified merchant has processed enough volume.
+type VolumePolicy struct {
+	// Thresholds maps currency → processed volume, in minor units, that
+	// triggers early KYC. Crossing any one of them triggers it.
+	Thresholds map[string]int64 `json:"thresholds"`
+	// EarlyDeadlineDays is how long the merchant has to submit documents
+	// after crossing a threshold. The deadline only ever moves earlier.
+	EarlyDeadlineDays int `json:"earlyDeadlineDays"`
+	// DailyVolumeCap, if positive, caps the merchant's daily volume (minor
+	// units) from the moment the threshold is crossed until documents arrive.
+	DailyVolumeCap int64 `json:"dailyVolumeCap,omitempty"`
+}
+
+// Validate checks that the policy can trigger early KYC and gives the
+// merchant time to respond.
+func (p VolumePolicy) Validate() error {
+	if len(p.Thresholds) == 0 {
+		return errors.New("thresholds must list at least one currency")
+	}
+	currencies := make([]string, 0, len(p.Thresholds))
+	for currency := range p.Thresholds {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		if currency == "" || p.Thresholds[currency] <= 0 {
+			return fmt.Errorf("threshold %q: %d must be a positive amount for a currency", currency, p.Thresholds[currency])
+		}
+	}
+	if p.EarlyDeadlineDays <= 0 {
+		return fmt.Errorf("earlyDeadlineDays %d must be positive", p.EarlyDeadlineDays)
+	}
+	if p.DailyVolumeCap < 0 {
+		return fmt.Errorf("dailyVolumeCap %d must not be negative", p.DailyVolumeCap)
+	}
+	return nil
+}
+
+// DefaultVolumePolicy is used when OnboardingRequest.VolumePolicy is unset.
+var DefaultVolumePolicy = VolumePolicy{
+	Thresholds: map[string]int64{
+		"EUR": 1_500_000, // €15,000
+		"GBP": 1_300_000,
+		"USD": 1_600_000,
+	},
+	EarlyDeadlineDays: 14,
+}
+
+// ReminderStep is one entry in ReminderSchedule.
+type ReminderStep struct {
+	At           time.Duration // Offset from workflow start.
+	ReminderType string
+	Urgency      string
+}
+
+// Reminder delivery channels.
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+)
+
 // ReminderRequest is the input to the SendReminder activity.
 type ReminderRequest struct {
 	MerchantID   string `json:"merchantId"`
 	Email        string `json:"email"`
-	ReminderType string `json:"reminderType"` // "day30", "day60", "final"
+	Phone        string `json:"phone,omitempty"`
+	Channel      string `json:"channel"`      // ChannelEmail or ChannelSMS
+	ReminderType string `json:"reminderType"` // "day30", "day60", "day83", "day87", "day89", "kycRejection", ...
+	Urgency      string `json:"urgency"`      // UrgencyStandard or UrgencyFinal
+	// IdempotencyKey identifies this reminder across activity retries and
+	// workflow resets. See ReminderIdempotencyKey.
+	IdempotencyKey string `json:"idempotencyKey"`
+	// Sequence numbers reminders of a type that can be sent more than once,
+	// such as manual resends, so each gets its own reminder ID. Zero for
+	// reminders sent once.
+	Sequence int `json:"sequence,omitempty"`
+}
+
+// ReminderIdempotencyKey derives the deterministic idempotency key for a
+// reminder. runID should be the workflow's first run ID, which is preserved
+// across resets, so a reset run produces the same keys as the original.
+func ReminderIdempotencyKey(workflowID, runID, reminderType string) string {
+	return fmt.Sprintf("%s/%s/%s", workflowID, runID, reminderType)
+}
+
+// DeliveryStatus is the outcome reported by the notification provider for a
+// reminder that was handed off for delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryDelivered  DeliveryStatus = "DELIVERED"
+	DeliveryBounced    DeliveryStatus = "BOUNCED"
+	DeliveryComplained DeliveryStatus = "COMPLAINED"
+)
+
+// Bounce types reported with DeliveryBounced.
+const (
+	BounceHard = "hard"
+	BounceSoft = "soft"
+)
+
+// DeliveryReceipt is delivered to the OnboardingWorkflow via
+// SignalDeliveryReceipt when the provider reports what happened to a reminder.
+type DeliveryReceipt struct {
+	MerchantID string         `json:"merchantId"`
+	ReminderID string         `json:"reminderId"`
+	Channel    string         `json:"channel"`
+	Status     DeliveryStatus `json:"status"`
+	BounceType string         `json:"bounceType,omitempty"` // BounceHard or BounceSoft
+	Reason     string         `json:"reason,omitempty"`
+}
+
+// IsHardBounce reports whether the receipt is a permanent delivery failure.
+func (r DeliveryReceipt) IsHardBounce() bool {
+	return r.Status == DeliveryBounced && r.BounceType == BounceHard
+}
+
+// EscalationRequest is the input to the EscalateToAccountManager activity.
+type EscalationRequest struct {
+	MerchantID          string `json:"merchantId"`
+	MerchantName        string `json:"merchantName"`
+	AccountManagerEmail string `json:"accountManagerEmail"`
+	Reason              string `json:"reason"`
+	FallbackChannel     string `json:"fallbackChannel,omitempty"` // Empty if no fallback is available.
+}
+
+// OpsAlert is the input to the NotifyOps activity: an internal alert for the
+// support and account-management teams.
+type OpsAlert struct {
+	MerchantID   string           `json:"merchantId"`
+	MerchantName string           `json:"merchantName"`
+	Country      string           `json:"country"`
+	Outcome      OnboardingStatus `json:"outcome"`
+	Reason       string           `json:"reason"`
+	Namespace    string           `json:"namespace"`
+	WorkflowID   string           `json:"workflowId"`
+	RunID        string           `json:"runId"`
+}
+
+// PaymentEvent is a payment processed for a merchant. The first one starts
+// the merchant's onboarding; every one is delivered to the OnboardingWorkflow
+// via SignalPaymentReceived.
+type PaymentEvent struct {
+	EventID    string       `json:"eventId"` // Unique per payment; used to drop redeliveries.
+	Merchant   MerchantInfo `json:"merchant"`
+	Amount     int64        `json:"amount"` // Minor units of Currency.
+	Currency   string       `json:"currency"`
+	OccurredAt time.Time    `json:"occurredAt"`
 }
 
 // DocumentUpload represents a document submitted by the merchant.
@@ -51,4 +404,51 @@ type VerificationResult struct {
 	Passed         bool   `json:"passed"`
 	VerificationID string `json:"verificationId"`
 	Details        string `json:"details"`
+	// ReasonCodes explain a failed result (see the Reason* constants).
+	ReasonCodes []string `json:"reasonCodes,omitempty"`
+	// Conditions, when set on a passed result, approves the merchant only
+	// with these processing conditions.
+	Conditions *MerchantConditions `json:"conditions,omitempty"`
+}
+
+// MerchantConditions are the processing conditions compliance can attach to
+// an approval.
+type MerchantConditions struct {
+	// MonthlyVolumeLimit caps processed volume per calendar month, in minor
+	// units of the settlement currency. 0 means no limit.
+	MonthlyVolumeLimit  int64  `json:"monthlyVolumeLimit,omitempty"`
+	NoThirdPartyPayouts bool   `json:"noThirdPartyPayouts,omitempty"`
+	EnhancedMonitoring  bool   `json:"enhancedMonitoring,omitempty"`
+	Reason              string `json:"reason,omitempty"`
+}
+
+// Any reports whether c imposes at least one condition.
+func (c MerchantConditions) Any() bool {
+	return c.MonthlyVolumeLimit > 0 || c.NoThirdPartyPayouts || c.EnhancedMonitoring
+}
+
+// Merge combines two sets of conditions, keeping the stricter of each.
+func (c MerchantConditions) Merge(other MerchantConditions) MerchantConditions {
+	merged := MerchantConditions{
+		MonthlyVolumeLimit:  c.MonthlyVolumeLimit,
+		NoThirdPartyPayouts: c.NoThirdPartyPayouts || other.NoThirdPartyPayouts,
+		EnhancedMonitoring:  c.EnhancedMonitoring || other.EnhancedMonitoring,
+		Reason:              c.Reason,
+	}
+	if other.MonthlyVolumeLimit > 0 && (merged.MonthlyVolumeLimit == 0 || other.MonthlyVolumeLimit < merged.MonthlyVolumeLimit) {
+		merged.MonthlyVolumeLimit = other.MonthlyVolumeLimit
+	}
+	switch {
+	case merged.Reason == "":
+		merged.Reason = other.Reason
+	case other.Reason != "" && other.Reason != merged.Reason:
+		merged.Reason += "; " + other.Reason
+	}
+	return merged
+}
+
+// MerchantConditionsRequest is the input to the ApplyMerchantConditions activity.
+type MerchantConditionsRequest struct {
+	MerchantID string             `json:"merchantId"`
+	Conditions MerchantConditions `json:"conditions"`
 }
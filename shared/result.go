@@ -0,0 +1,112 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Outcome is how an onboarding ended.
+type Outcome string
+
+const (
+	OutcomeApproved               Outcome = "APPROVED"
+	OutcomeApprovedWithConditions Outcome = "APPROVED_WITH_CONDITIONS"
+	OutcomeKYCRejected            Outcome = "KYC_REJECTED"
+	OutcomePaymentsDisabled       Outcome = "PAYMENTS_DISABLED"
+)
+
+// legacyResultSuffixes maps outcomes to the suffix of the result strings
+// ("ONBOARD-{merchantId}-{suffix}") the workflow returned before
+// OnboardingResult. Longest suffix first, so parsing matches
+// APPROVED-WITH-CONDITIONS before APPROVED.
+var legacyResultSuffixes = []struct {
+	outcome Outcome
+	suffix  string
+}{
+	{OutcomeApprovedWithConditions, "APPROVED-WITH-CONDITIONS"},
+	{OutcomePaymentsDisabled, "PAYMENTS-DISABLED"},
+	{OutcomeKYCRejected, "KYC-REJECTED"},
+	{OutcomeApproved, "APPROVED"},
+}
+
+// LegacyResultCode returns the result string the workflow used to return for
+// this outcome, e.g. "ONBOARD-MERCH-001-APPROVED".
+func LegacyResultCode(merchantID string, outcome Outcome) string {
+	for _, s := range legacyResultSuffixes {
+		if s.outcome == outcome {
+			return fmt.Sprintf("ONBOARD-%s-%s", merchantID, s.suffix)
+		}
+	}
+	return fmt.Sprintf("ONBOARD-%s-%s", merchantID, outcome)
+}
+
+// OnboardingResult is the result of OnboardingWorkflow.
+type OnboardingResult struct {
+	Outcome    Outcome `json:"outcome"`
+	MerchantID string  `json:"merchantId"`
+
+	StartedAt           time.Time  `json:"startedAt"`
+	DocumentSubmittedAt *time.Time `json:"documentSubmittedAt,omitempty"`
+	DecidedAt           time.Time  `json:"decidedAt"`
+
+	VerificationID string              `json:"verificationId,omitempty"`
+	ReasonCodes    []string            `json:"reasonCodes,omitempty"`
+	Conditions     *MerchantConditions `json:"conditions,omitempty"`
+
+	RemindersSent       []string           `json:"remindersSent,omitempty"` // Reminder types, in send order.
+	RestrictionsApplied []RestrictionLevel `json:"restrictionsApplied,omitempty"`
+
+	// ResultCode is the result string older versions returned.
+	ResultCode string `json:"resultCode"`
+
+	// Legacy marks the result of an execution that started before typed
+	// results. It is encoded as the bare ResultCode string, so callers of
+	// those executions still get the string they expect.
+	Legacy bool `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r OnboardingResult) MarshalJSON() ([]byte, error) {
+	if r.Legacy {
+		return json.Marshal(r.ResultCode)
+	}
+	type plain OnboardingResult
+	return json.Marshal(plain(r))
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It also accepts the result
+// strings of executions that completed before typed results, filling in
+// Outcome, MerchantID and ResultCode.
+func (r *OnboardingResult) UnmarshalJSON(data []byte) error {
+	var code string
+	if err := json.Unmarshal(data, &code); err == nil {
+		parsed, err := ParseLegacyResult(code)
+		if err != nil {
+			return err
+		}
+		*r = parsed
+		return nil
+	}
+	type plain OnboardingResult
+	return json.Unmarshal(data, (*plain)(r))
+}
+
+// ParseLegacyResult parses a result string such as
+// "ONBOARD-MERCH-001-KYC-REJECTED".
+func ParseLegacyResult(code string) (OnboardingResult, error) {
+	rest, ok := strings.CutPrefix(code, "ONBOARD-")
+	if ok {
+		for _, s := range legacyResultSuffixes {
+			if merchantID, found := strings.CutSuffix(rest, "-"+s.suffix); found && merchantID != "" {
+				return OnboardingResult{
+					Outcome:    s.outcome,
+					MerchantID: merchantID,
+					ResultCode: code,
+				}, nil
+			}
+		}
+	}
+	return OnboardingResult{}, fmt.Errorf("unrecognised onboarding result %q", code)
+}
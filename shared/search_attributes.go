@@ -0,0 +1,34 @@
+package shared
+
+import (
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+)
+
+// Search attributes OnboardingWorkflow keeps up to date, so onboardings can
+// be found with visibility queries such as
+//
+//	Country = 'NL' AND OnboardingStatus = 'AWAITING_KYC_DOCUMENTS' AND Deadline < '2026-04-01T00:00:00Z'
+//
+// They must be registered on the namespace before workers start; see
+// SearchAttributeTypes.
+var (
+	SearchAttrMerchantID       = temporal.NewSearchAttributeKeyKeyword("MerchantID")
+	SearchAttrCountry          = temporal.NewSearchAttributeKeyKeyword("Country")
+	SearchAttrBusinessType     = temporal.NewSearchAttributeKeyKeyword("BusinessType")
+	SearchAttrOnboardingStatus = temporal.NewSearchAttributeKeyKeyword("OnboardingStatus")
+	SearchAttrDeadline         = temporal.NewSearchAttributeKeyTime("Deadline")
+	SearchAttrKYCAttempts      = temporal.NewSearchAttributeKeyInt64("KYCAttempts")
+)
+
+// SearchAttributeTypes lists the custom search attributes with their
+// types, in the form `temporal operator search-attribute create` and
+// `temporal server start-dev --search-attribute` expect.
+var SearchAttributeTypes = map[string]enums.IndexedValueType{
+	SearchAttrMerchantID.GetName():       enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttrCountry.GetName():          enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttrBusinessType.GetName():     enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttrOnboardingStatus.GetName(): enums.INDEXED_VALUE_TYPE_KEYWORD,
+	SearchAttrDeadline.GetName():         enums.INDEXED_VALUE_TYPE_DATETIME,
+	SearchAttrKYCAttempts.GetName():      enums.INDEXED_VALUE_TYPE_INT,
+}
@@ -1,146 +1,65 @@
 package main
 
 import (
-	"bufio"
 	"context"
-	"fmt"
 	"log"
+	"log/slog"
 	"os"
-	"strings"
+	"os/signal"
 
 	"go.temporal.io/sdk/client"
+	tlog "go.temporal.io/sdk/log"
 
-	"temporal-customer-onboarding/shared"
-	"temporal-customer-onboarding/workflows"
+	"temporal-customer-onboarding/cli"
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/tracing"
 )
 
 func main() {
-	c, err := client.Dial(client.Options{})
+	// Lazy, so usage errors don't need a reachable server. SDK logging is
+	// limited to warnings to keep the output scriptable.
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Unable to create Temporal client: %v", err)
-	}
-	defer c.Close()
-
-	merchantID := "MERCH-001"
-	// Business-meaningful workflow ID acts as an idempotency key: prevents
-	// duplicate onboarding for the same merchant while a workflow is running.
-	workflowID := fmt.Sprintf("onboard-merchant-%s", merchantID)
-	reader := bufio.NewReader(os.Stdin)
-
-	req := shared.OnboardingRequest{
-		Merchant: shared.MerchantInfo{
-			MerchantID:   merchantID,
-			Name:         "Acme Online Store",
-			Email:        "onboarding@acme-store.com",
-			Country:      "NL",
-			BusinessType: "ecommerce",
-		},
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-
-	fmt.Println()
-	fmt.Println("🚀 Starting onboarding workflow for merchant", merchantID)
-
-	// To also reject re-onboarding after completion, add WorkflowIDReusePolicy:
-	//   enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE
-	we, err := c.ExecuteWorkflow(
-		context.Background(),
-		client.StartWorkflowOptions{
-			ID:        workflowID,
-			TaskQueue: shared.OnboardingWorkflowTaskQueue,
-		},
-		workflows.OnboardingWorkflow,
-		req,
-	)
+	opts, err := cfg.ClientOptions()
 	if err != nil {
-		log.Fatalf("Unable to start workflow: %v", err)
-	}
-	fmt.Printf("   WorkflowID: %s\n", we.GetID())
-	fmt.Printf("   RunID:      %s\n", we.GetRunID())
-
-	// Interactive menu loop.
-	for {
-		fmt.Println()
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println("  Merchant Onboarding CLI")
-		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-		fmt.Println()
-		fmt.Println("  [1] Submit document (triggers KYC verification)")
-		fmt.Println("  [2] Query workflow status")
-		fmt.Println("  [3] Exit (workflow continues running)")
-		fmt.Println()
-		fmt.Print("Choose: ")
-
-		choice, _ := reader.ReadString('\n')
-		choice = strings.TrimSpace(choice)
-
-		switch choice {
-		case "1":
-			handleSubmitDocument(c, workflowID, we, reader)
-			return
-
-		case "2":
-			handleQueryStatus(c, workflowID)
-
-		case "3":
-			fmt.Println()
-			fmt.Println("👋 Exiting CLI. The workflow continues running in Temporal.")
-			fmt.Println("   Re-run this program to reconnect, or view at http://localhost:8233")
-			return
-
-		default:
-			fmt.Println("❌ Invalid choice. Please enter 1, 2, or 3.")
-		}
+		log.Fatalf("Invalid configuration: %v", err)
 	}
-}
-
-func handleSubmitDocument(c client.Client, workflowID string, we client.WorkflowRun, reader *bufio.Reader) {
-	fmt.Println()
-	fmt.Print("Enter your government ID document number (digits only to pass): ")
-	documentID, _ := reader.ReadString('\n')
-	documentID = strings.TrimSpace(documentID)
-
-	fmt.Printf("\n📤 Sending document ID '%s' to workflow...\n", documentID)
-
-	err := c.SignalWorkflow(
-		context.Background(),
-		workflowID,
-		"",
-		shared.SignalDocumentSubmitted,
-		documentID,
-	)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	opts.Logger = tlog.NewStructuredLogger(logger)
+	// Starts and signals begin the trace the workers continue.
+	tracer, err := tracing.Setup(context.Background(), cfg.Tracing, "onboarding-starter")
 	if err != nil {
-		log.Fatalf("Unable to signal workflow: %v", err)
+		log.Fatalf("Unable to set up tracing: %v", err)
 	}
-	fmt.Println("✅ Signal sent! Waiting for workflow result...")
-	fmt.Println()
-
-	// Wait for the workflow to complete.
-	var result string
-	err = we.Get(context.Background(), &result)
+	opts.Interceptors = append(opts.Interceptors, tracer.Interceptors...)
+	c, err := client.NewLazyClient(opts)
 	if err != nil {
-		log.Fatalf("Workflow failed: %v", err)
+		log.Fatalf("Unable to create Temporal client: %v", err)
 	}
 
-	fmt.Printf("🏁 Result: %s\n", result)
-}
-
-func handleQueryStatus(c client.Client, workflowID string) {
-	resp, err := c.QueryWorkflow(
-		context.Background(),
-		workflowID,
-		"",
-		shared.QueryOnboardingStatus,
-	)
-	if err != nil {
-		fmt.Printf("❌ Query failed: %v\n", err)
-		return
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	app := &cli.CLI{
+		Service: newService(c, cfg),
+		Stdout:  os.Stdout,
+		Stderr:  os.Stderr,
+		Name:    "starter",
 	}
+	code := app.Run(ctx, os.Args[1:])
 
-	var statusResp shared.OnboardingStatusResponse
-	if err := resp.Get(&statusResp); err != nil {
-		fmt.Printf("❌ Failed to decode status: %v\n", err)
-		return
-	}
+	stop()
+	c.Close()
+	_ = tracer.Shutdown(context.Background())
+	os.Exit(code)
+}
 
-	fmt.Printf("\n📋 Status: %s (%d days remaining)\n", statusResp.Status, statusResp.DaysRemaining)
+// newService returns an onboarding service routed to the configured task
+// queues.
+func newService(c client.Client, cfg config.Config) *onboarding.Service {
+	svc := onboarding.NewService(c)
+	svc.TaskQueue = cfg.TaskQueues.Workflow
+	svc.ActivityTaskQueue = cfg.TaskQueues.Activity
+	return svc
 }
@@ -1,9 +1,13 @@
 package tests
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/testsuite"
 
@@ -32,6 +36,28 @@ func TestSendReminder(t *testing.T) {
 	assert.Equal(t, "REMIND-MERCH-001-day30", reminderID)
 }
 
+func TestSendReminder_RepeatedSendsGetTheirOwnID(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{}
+	env.RegisterActivity(a.SendReminder)
+
+	var ids []string
+	for seq := 1; seq <= 2; seq++ {
+		result, err := env.ExecuteActivity(a.SendReminder, shared.ReminderRequest{
+			MerchantID:   "MERCH-001",
+			Email:        "test@example.com",
+			ReminderType: "manual",
+			Sequence:     seq,
+		})
+		require.NoError(t, err)
+		var reminderID string
+		require.NoError(t, result.Get(&reminderID))
+		ids = append(ids, reminderID)
+	}
+	assert.Equal(t, []string{"REMIND-MERCH-001-manual-1", "REMIND-MERCH-001-manual-2"}, ids)
+}
+
 func TestDisablePayments(t *testing.T) {
 	testSuite := &testsuite.WorkflowTestSuite{}
 	env := testSuite.NewTestActivityEnvironment()
@@ -99,3 +125,70 @@ func TestPerformInternalVerifications(t *testing.T) {
 	assert.True(t, verResult.Passed)
 	assert.Equal(t, "INT-MERCH-001", verResult.VerificationID)
 }
+
+func TestSendReminder_DeduplicatesByIdempotencyKey(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "sent-reminders.jsonl")
+	store, err := activities.OpenFileSentReminderStore(storePath)
+	assert.NoError(t, err)
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{SentReminders: store}
+	env.RegisterActivity(a.SendReminder)
+
+	req := shared.ReminderRequest{
+		MerchantID:     "MERCH-001",
+		Email:          "test@example.com",
+		ReminderType:   "day30",
+		IdempotencyKey: shared.ReminderIdempotencyKey("onboard-merchant-MERCH-001", "run-1", "day30"),
+	}
+
+	// A retry with the same key returns the original reminder ID.
+	for i := 0; i < 2; i++ {
+		result, err := env.ExecuteActivity(a.SendReminder, req)
+		assert.NoError(t, err)
+		var reminderID string
+		assert.NoError(t, result.Get(&reminderID))
+		assert.Equal(t, "REMIND-MERCH-001-day30", reminderID)
+	}
+	assert.NoError(t, store.Close())
+
+	// The key survives a worker restart.
+	reopened, err := activities.OpenFileSentReminderStore(storePath)
+	assert.NoError(t, err)
+	defer reopened.Close()
+
+	reminderID, found, err := reopened.Lookup(req.IdempotencyKey)
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "REMIND-MERCH-001-day30", reminderID)
+
+	data, err := os.ReadFile(storePath)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(string(data), "\n"), "key should be recorded exactly once")
+}
+
+func TestFileSentReminderStore_RecoversFromTornWrite(t *testing.T) {
+	storePath := filepath.Join(t.TempDir(), "sent-reminders.jsonl")
+	// A crash left the second record half written.
+	require.NoError(t, os.WriteFile(storePath, []byte(
+		`{"key":"k1","reminderId":"REMIND-1"}`+"\n"+`{"key":"k2","remi`,
+	), 0o644))
+
+	store, err := activities.OpenFileSentReminderStore(storePath)
+	require.NoError(t, err)
+	_, found, _ := store.Lookup("k2")
+	assert.False(t, found)
+	require.NoError(t, store.Record("k3", "REMIND-3"))
+	require.NoError(t, store.Close())
+
+	reopened, err := activities.OpenFileSentReminderStore(storePath)
+	require.NoError(t, err)
+	defer reopened.Close()
+	for key, want := range map[string]string{"k1": "REMIND-1", "k3": "REMIND-3"} {
+		id, found, err := reopened.Lookup(key)
+		require.NoError(t, err)
+		assert.True(t, found, key)
+		assert.Equal(t, want, id)
+	}
+}
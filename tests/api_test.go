@@ -0,0 +1,216 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/api"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+const apiToken = "portal-secret"
+
+// envService serves the API from a TestWorkflowEnvironment. Start only
+// records the request; the test then executes the workflow with it.
+type envService struct {
+	env     *testsuite.TestWorkflowEnvironment
+	started []shared.OnboardingRequest
+}
+
+func (s *envService) known(merchantID string) error {
+	if len(s.started) == 0 || s.started[0].Merchant.MerchantID != merchantID {
+		return onboarding.ErrNotFound
+	}
+	return nil
+}
+
+func (s *envService) Start(_ context.Context, req shared.OnboardingRequest) (onboarding.Execution, error) {
+	if len(s.started) > 0 {
+		return onboarding.Execution{}, onboarding.ErrAlreadyStarted
+	}
+	s.started = append(s.started, req)
+	return onboarding.Execution{WorkflowID: shared.OnboardingWorkflowID(req.Merchant.MerchantID), RunID: "test-run"}, nil
+}
+
+func (s *envService) SubmitDocument(_ context.Context, merchantID, documentID string) error {
+	if err := s.known(merchantID); err != nil {
+		return err
+	}
+	s.env.SignalWorkflow(shared.SignalDocumentSubmitted, documentID)
+	return nil
+}
+
+func (s *envService) Status(_ context.Context, merchantID string) (shared.OnboardingStatusResponse, error) {
+	var status shared.OnboardingStatusResponse
+	if err := s.known(merchantID); err != nil {
+		return status, err
+	}
+	resp, err := s.env.QueryWorkflow(shared.QueryOnboardingStatus)
+	if err != nil {
+		return status, err
+	}
+	return status, resp.Get(&status)
+}
+
+func (s *envService) Result(_ context.Context, merchantID string, _ bool) (shared.OnboardingResult, error) {
+	var result shared.OnboardingResult
+	if err := s.known(merchantID); err != nil {
+		return result, err
+	}
+	if !s.env.IsWorkflowCompleted() {
+		return result, onboarding.ErrNotCompleted
+	}
+	return result, s.env.GetWorkflowResult(&result)
+}
+
+func (s *envService) List(context.Context, onboarding.ListFilter) ([]onboarding.Summary, error) {
+	return nil, nil
+}
+
+func apiRequest(h http.Handler, method, path, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, path, strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+apiToken)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func apiErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body), rec.Body.String())
+	assert.NotEmpty(t, body.Error.Message)
+	return body.Error.Code
+}
+
+func TestAPI_OnboardingLifecycle(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+
+	svc := &envService{env: env}
+	server := api.NewServer(svc, apiToken)
+
+	rec := apiRequest(server, http.MethodPost, "/v1/onboardings",
+		`{"merchant":{"merchantId":"MERCH-001","name":"Test Store","email":"test@example.com","country":"NL"}}`)
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, "/v1/onboardings/MERCH-001", rec.Header().Get("Location"))
+	var exec onboarding.Execution
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &exec))
+	assert.Equal(t, "onboard-merchant-MERCH-001", exec.WorkflowID)
+
+	rec = apiRequest(server, http.MethodPost, "/v1/onboardings",
+		`{"merchant":{"merchantId":"MERCH-001","email":"test@example.com","country":"NL"}}`)
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, api.CodeAlreadyStarted, apiErrorCode(t, rec))
+
+	env.RegisterDelayedCallback(func() {
+		rec := apiRequest(server, http.MethodGet, "/v1/onboardings/MERCH-001", "")
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var status shared.OnboardingStatusResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+		assert.Equal(t, shared.StatusRemindersActive, status.Status)
+
+		rec = apiRequest(server, http.MethodGet, "/v1/onboardings/MERCH-001/result", "")
+		assert.Equal(t, http.StatusConflict, rec.Code)
+		assert.Equal(t, api.CodeOnboardingRunning, apiErrorCode(t, rec))
+
+		rec = apiRequest(server, http.MethodPost, "/v1/onboardings/MERCH-001/documents", `{"documentId":"123456789"}`)
+		assert.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+	}, 24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, svc.started[0])
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	rec = apiRequest(server, http.MethodGet, "/v1/onboardings/MERCH-001/result", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var result shared.OnboardingResult
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, shared.OutcomeApproved, result.Outcome)
+	assert.Equal(t, "KYC-MERCH-001", result.VerificationID)
+
+	rec = apiRequest(server, http.MethodGet, "/v1/onboardings/MERCH-001", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"status":"APPROVED"`)
+}
+
+func TestAPI_ValidatesRequests(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{Status: shared.StatusRemindersActive}
+	server := api.NewServer(svc, apiToken)
+
+	tests := []struct {
+		name, method, path, body string
+		wantStatus               int
+		wantCode                 string
+	}{
+		{"malformed JSON", http.MethodPost, "/v1/onboardings", `{"merchant":`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"unknown field", http.MethodPost, "/v1/onboardings", `{"merchant":{"merchantId":"M-1","email":"a@b.example","country":"NL"},"priority":1}`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"missing merchant ID", http.MethodPost, "/v1/onboardings", `{"merchant":{"email":"a@b.example","country":"NL"}}`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"bad country", http.MethodPost, "/v1/onboardings", `{"merchant":{"merchantId":"M-1","email":"a@b.example","country":"nl"}}`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"bad send window", http.MethodPost, "/v1/onboardings", `{"merchant":{"merchantId":"M-1","email":"a@b.example","country":"NL"},"reminderWindow":{"startHour":18,"endHour":9}}`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"missing document ID", http.MethodPost, "/v1/onboardings/MERCH-001/documents", `{}`, http.StatusBadRequest, api.CodeInvalidRequest},
+		{"unknown merchant", http.MethodGet, "/v1/onboardings/MERCH-404", "", http.StatusNotFound, api.CodeNotFound},
+		{"unknown endpoint", http.MethodGet, "/v1/merchants", "", http.StatusNotFound, api.CodeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := apiRequest(server, tt.method, tt.path, tt.body)
+			assert.Equal(t, tt.wantStatus, rec.Code, rec.Body.String())
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			assert.Equal(t, tt.wantCode, apiErrorCode(t, rec))
+		})
+	}
+	assert.Empty(t, svc.started)
+	assert.Empty(t, svc.submitted)
+}
+
+func TestAPI_RequiresBearerToken(t *testing.T) {
+	server := api.NewServer(newFakeService(), apiToken)
+
+	for name, header := range map[string]string{
+		"missing":      "",
+		"wrong token":  "Bearer guess",
+		"wrong scheme": "Basic " + apiToken,
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/v1/onboardings/MERCH-001", nil)
+			if header != "" {
+				r.Header.Set("Authorization", header)
+			}
+			rec := httptest.NewRecorder()
+			server.ServeHTTP(rec, r)
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			assert.NotEmpty(t, rec.Header().Get("WWW-Authenticate"))
+			assert.Equal(t, api.CodeUnauthorized, apiErrorCode(t, rec))
+		})
+	}
+
+	// The spec is public so the portal team can generate a client from it.
+	rec := httptest.NewRecorder()
+	server.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.yaml", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/v1/onboardings/{merchantId}/result")
+}
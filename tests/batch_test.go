@@ -0,0 +1,234 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/batch"
+	"temporal-customer-onboarding/cli"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_AppliesRepeatedBatchSignalsOnce(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	var manual []shared.ReminderRequest
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			if req.ReminderType == "manual" {
+				manual = append(manual, req)
+			}
+			return "REMIND-" + req.ReminderType, nil
+		},
+	)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+
+	// A resumed batch signals again with the same request ID.
+	env.RegisterDelayedCallback(func() {
+		for i := 0; i < 2; i++ {
+			env.SignalWorkflow(shared.SignalExtendDeadline, shared.DeadlineExtension{Days: 7, RequestID: "batch-1"})
+			env.SignalWorkflow(shared.SignalResendReminder, shared.ReminderResend{Reason: "portal outage", RequestID: "batch-1"})
+		}
+	}, 10*24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.True(t, queryStatus(t, env).Deadline.Equal(onboardingStartTime.Add(shared.DeadlineDay90+7*24*time.Hour)))
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 11*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.Len(t, manual, 1)
+	assert.Equal(t, 1, manual[0].Sequence)
+	assert.Contains(t, manual[0].IdempotencyKey, "manual:batch-1")
+}
+
+// batchService is a concurrency-safe batch.Service over a set of running
+// onboardings. Operations on merchants in fail return an error.
+type batchService struct {
+	mu       sync.Mutex
+	running  []string
+	fail     map[string]bool
+	requests map[string][]string // merchant ID → request IDs received
+	cancels  []string
+}
+
+func newBatchService(running ...string) *batchService {
+	return &batchService{running: running, fail: make(map[string]bool), requests: make(map[string][]string)}
+}
+
+func (s *batchService) List(_ context.Context, filter onboarding.ListFilter) ([]onboarding.Summary, error) {
+	var summaries []onboarding.Summary
+	for _, id := range s.running {
+		summaries = append(summaries, onboarding.Summary{MerchantID: id, WorkflowID: shared.OnboardingWorkflowID(id), Status: filter.Status})
+	}
+	return summaries, nil
+}
+
+func (s *batchService) signal(merchantID, requestID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail[merchantID] {
+		return errors.New("frontend unavailable")
+	}
+	s.requests[merchantID] = append(s.requests[merchantID], requestID)
+	return nil
+}
+
+func (s *batchService) ExtendDeadline(_ context.Context, merchantID string, ext shared.DeadlineExtension) error {
+	return s.signal(merchantID, ext.RequestID)
+}
+
+func (s *batchService) ResendReminder(_ context.Context, merchantID string, resend shared.ReminderResend) error {
+	return s.signal(merchantID, resend.RequestID)
+}
+
+func (s *batchService) Cancel(_ context.Context, merchantID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if merchantID == "M-GONE" {
+		return onboarding.ErrNotFound
+	}
+	s.cancels = append(s.cancels, merchantID)
+	return nil
+}
+
+func TestBatch_ResumesFromCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extend.ckpt")
+	svc := newBatchService("M-1", "M-2", "M-3")
+	svc.fail["M-2"] = true
+	op := batch.Operation{ID: "batch-first", Action: batch.ActionExtend, Days: 14, Filter: onboarding.ListFilter{Country: "NL"}}
+
+	cp, err := batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	results, err := (&batch.Runner{Service: svc, Checkpoint: cp}).Run(context.Background(), op)
+	require.NoError(t, err)
+	require.NoError(t, cp.Close())
+	assert.Equal(t, map[batch.Status]int{batch.StatusApplied: 2, batch.StatusFailed: 1}, batch.Summary(results))
+
+	// The rerun gets a fresh ID but adopts the recorded one, and only
+	// signals the onboarding that failed.
+	svc.fail["M-2"] = false
+	op.ID = "batch-second"
+	cp, err = batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	assert.Equal(t, "batch-first", cp.Operation.ID)
+	results, err = (&batch.Runner{Service: svc, Checkpoint: cp}).Run(context.Background(), cp.Operation)
+	require.NoError(t, err)
+	require.NoError(t, cp.Close())
+	assert.Equal(t, map[batch.Status]int{batch.StatusDone: 2, batch.StatusApplied: 1}, batch.Summary(results))
+	assert.Equal(t, map[string][]string{
+		"M-1": {"batch-first"},
+		"M-2": {"batch-first"},
+		"M-3": {"batch-first"},
+	}, svc.requests)
+
+	// A different operation can't reuse the file.
+	op.Days = 30
+	_, err = batch.OpenCheckpoint(path, op)
+	assert.ErrorContains(t, err, "different batch")
+}
+
+// A crash mid-Record leaves a torn last line. Resuming drops it, and the
+// next Record must start on a fresh line so the batch can resume again.
+func TestCheckpoint_TornLineIsTruncated(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extend.ckpt")
+	op := batch.Operation{ID: "batch-torn", Action: batch.ActionExtend, Days: 14}
+
+	cp, err := batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	require.NoError(t, cp.Record("onboard-merchant-M-1", time.Now()))
+	require.NoError(t, cp.Close())
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"workflowId":"onboard-merch`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	cp, err = batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cp.Len())
+	require.NoError(t, cp.Record("onboard-merchant-M-2", time.Now()))
+	require.NoError(t, cp.Close())
+
+	cp, err = batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	assert.Equal(t, 2, cp.Len())
+	assert.True(t, cp.Done("onboard-merchant-M-1"))
+	assert.True(t, cp.Done("onboard-merchant-M-2"))
+}
+
+func TestBatch_DryRunAndFinishedOnboardings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cancel.ckpt")
+	svc := newBatchService("M-1", "M-GONE")
+	op := batch.Operation{ID: "batch-cancel", Action: batch.ActionCancel}
+
+	cp, err := batch.OpenCheckpoint(path, op)
+	require.NoError(t, err)
+	results, err := (&batch.Runner{Service: svc, Checkpoint: cp, DryRun: true}).Run(context.Background(), op)
+	require.NoError(t, err)
+	assert.Equal(t, map[batch.Status]int{batch.StatusPlanned: 2}, batch.Summary(results))
+	assert.Empty(t, svc.cancels)
+	assert.NoFileExists(t, path)
+
+	results, err = (&batch.Runner{Service: svc, Checkpoint: cp}).Run(context.Background(), op)
+	require.NoError(t, err)
+	require.NoError(t, cp.Close())
+	assert.Equal(t, []batch.Status{batch.StatusApplied, batch.StatusFinished},
+		[]batch.Status{results[0].Status, results[1].Status})
+
+	_, err = (&batch.Runner{Service: svc, MaxTargets: 1}).Run(context.Background(), op)
+	assert.ErrorIs(t, err, onboarding.ErrInvalidRequest)
+}
+
+func TestCLI_BatchRemind(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{}
+	path := filepath.Join(t.TempDir(), "remind.ckpt")
+
+	code, stdout, stderr := runCLI(svc, "batch", "remind", "-country", "NL", "-query", "KYCAttempts = 0",
+		"-reason", "portal outage", "-concurrency", "1", "-checkpoint", path)
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Contains(t, stdout, "1 applied")
+	assert.Contains(t, stderr, "[1/1] MERCH-001 applied")
+	assert.Equal(t, []onboarding.ListFilter{{Status: "Running", Country: "NL", Where: "KYCAttempts = 0", Limit: 10001}}, svc.listed)
+	assert.True(t, strings.HasPrefix(svc.resends["MERCH-001"].RequestID, "batch-remind-"))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), svc.resends["MERCH-001"].RequestID)
+
+	code, stdout, _ = runCLI(svc, "batch", "remind", "-country", "NL", "-query", "KYCAttempts = 0",
+		"-reason", "portal outage", "-checkpoint", path)
+	require.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "1 already done")
+
+	for _, args := range [][]string{
+		{"batch", "archive"},
+		{"batch", "extend"},
+		{"batch", "remind", "-days", "3"},
+	} {
+		code, _, _ := runCLI(svc, args...)
+		assert.Equal(t, cli.ExitUsage, code, args)
+	}
+}
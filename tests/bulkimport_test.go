@@ -0,0 +1,171 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"temporal-customer-onboarding/bulkimport"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+var importNow = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// recordingStarter starts onboardings in memory and tracks concurrency.
+type recordingStarter struct {
+	mu       sync.Mutex
+	started  map[string]shared.OnboardingRequest
+	inFlight int
+	maxSeen  int
+	failFor  map[string]error
+}
+
+func newRecordingStarter() *recordingStarter {
+	return &recordingStarter{started: make(map[string]shared.OnboardingRequest), failFor: make(map[string]error)}
+}
+
+func (s *recordingStarter) Start(_ context.Context, req shared.OnboardingRequest) (onboarding.Execution, error) {
+	s.mu.Lock()
+	s.inFlight++
+	s.maxSeen = max(s.maxSeen, s.inFlight)
+	s.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.inFlight--
+	id := req.Merchant.MerchantID
+	if err := s.failFor[id]; err != nil {
+		return onboarding.Execution{}, err
+	}
+	if _, ok := s.started[id]; ok {
+		return onboarding.Execution{}, onboarding.ErrAlreadyStarted
+	}
+	s.started[id] = req
+	return onboarding.Execution{WorkflowID: shared.OnboardingWorkflowID(id), RunID: "run-" + id}, nil
+}
+
+func TestReadCSV_ParsesRowsByHeader(t *testing.T) {
+	input := `merchantId,name,email,country,firstPaymentAt,documentId
+MERCH-001,Acme,ops@acme.example,NL,2026-02-01,
+MERCH-002,Beta,ops@beta.example,DE,2026-02-10T09:30:00+01:00,123456
+MERCH-003,Gamma,ops@gamma.example,FR,yesterday,
+MERCH-004,Delta
+`
+	rows, err := bulkimport.ReadCSV(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, 2, rows[0].Line)
+	assert.Equal(t, "MERCH-001", rows[0].Merchant.MerchantID)
+	assert.True(t, rows[0].FirstPaymentAt.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Empty(t, rows[0].DocumentID)
+
+	assert.Equal(t, "123456", rows[1].DocumentID)
+	assert.True(t, rows[1].FirstPaymentAt.Equal(time.Date(2026, 2, 10, 8, 30, 0, 0, time.UTC)))
+
+	assert.ErrorContains(t, rows[2].Err, "firstPaymentAt")
+	assert.ErrorContains(t, rows[3].Err, "expected 6 fields")
+
+	_, err = bulkimport.ReadCSV(strings.NewReader("merchantId,shoeSize\n"))
+	assert.ErrorContains(t, err, `unknown CSV column "shoeSize"`)
+}
+
+func TestReadJSONL_ParsesMerchantRows(t *testing.T) {
+	input := `{"merchantId":"MERCH-001","email":"ops@acme.example","country":"NL","riskTier":"high","firstPaymentAt":"2026-02-01"}
+
+{"merchantId":
+`
+	rows, err := bulkimport.ReadJSONL(strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, shared.RiskTierHigh, rows[0].Merchant.RiskTier)
+	assert.NotNil(t, rows[0].FirstPaymentAt)
+	assert.Equal(t, 3, rows[1].Line)
+	assert.Error(t, rows[1].Err)
+}
+
+func TestValidate_RejectsBadRows(t *testing.T) {
+	valid := bulkimport.Row{Merchant: shared.MerchantInfo{MerchantID: "MERCH-001", Email: "ops@acme.example", Country: "NL"}}
+	require.NoError(t, bulkimport.Validate(valid, importNow))
+
+	future := importNow.Add(time.Hour)
+	expired := importNow.Add(-shared.DeadlineDay90)
+	tests := map[string]func(r *bulkimport.Row){
+		"no merchant ID":  func(r *bulkimport.Row) { r.Merchant.MerchantID = "" },
+		"bad email":       func(r *bulkimport.Row) { r.Merchant.Email = "acme.example" },
+		"bad country":     func(r *bulkimport.Row) { r.Merchant.Country = "nld" },
+		"bad risk tier":   func(r *bulkimport.Row) { r.Merchant.RiskTier = "extreme" },
+		"bad timezone":    func(r *bulkimport.Row) { r.Merchant.Timezone = "Mars/Olympus" },
+		"future payment":  func(r *bulkimport.Row) { r.FirstPaymentAt = &future },
+		"expired payment": func(r *bulkimport.Row) { r.FirstPaymentAt = &expired },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			row := valid
+			mutate(&row)
+			assert.Error(t, bulkimport.Validate(row, importNow))
+		})
+	}
+}
+
+func TestImporter_ReportsEveryRow(t *testing.T) {
+	starter := newRecordingStarter()
+	starter.started["MERCH-002"] = shared.OnboardingRequest{} // Already running.
+	starter.failFor["MERCH-005"] = errors.New("connection refused")
+
+	merchant := func(line int, id string) bulkimport.Row {
+		return bulkimport.Row{Line: line, Merchant: shared.MerchantInfo{MerchantID: id, Email: "ops@example.com", Country: "NL"}}
+	}
+	rows := []bulkimport.Row{
+		merchant(2, "MERCH-001"),
+		merchant(3, "MERCH-002"),
+		merchant(4, "MERCH-001"), // Duplicate in the file.
+		{Line: 5, Merchant: shared.MerchantInfo{MerchantID: "MERCH-004"}},
+		merchant(6, "MERCH-005"),
+		merchant(7, "MERCH-006"),
+	}
+
+	im := &bulkimport.Importer{Starter: starter, Concurrency: 2, Now: func() time.Time { return importNow }}
+	results := im.Run(context.Background(), rows)
+
+	var statuses []bulkimport.Status
+	for _, r := range results {
+		statuses = append(statuses, r.Status)
+	}
+	assert.Equal(t, []bulkimport.Status{
+		bulkimport.StatusStarted,
+		bulkimport.StatusSkipped,
+		bulkimport.StatusInvalid,
+		bulkimport.StatusInvalid,
+		bulkimport.StatusFailed,
+		bulkimport.StatusStarted,
+	}, statuses)
+	assert.Equal(t, "onboard-merchant-MERCH-006", results[5].WorkflowID)
+	assert.Contains(t, results[2].Error, "first seen on line 2")
+	assert.LessOrEqual(t, starter.maxSeen, 2)
+
+	var report strings.Builder
+	require.NoError(t, bulkimport.WriteReport(&report, results))
+	assert.Contains(t, report.String(), "3,MERCH-002,skipped,onboard-merchant-MERCH-002,,merchant already has an onboarding\n")
+}
+
+func TestImporter_DryRunStartsNothing(t *testing.T) {
+	starter := newRecordingStarter()
+	im := &bulkimport.Importer{Starter: starter, DryRun: true, Now: func() time.Time { return importNow }}
+
+	results := im.Run(context.Background(), []bulkimport.Row{
+		{Line: 2, Merchant: shared.MerchantInfo{MerchantID: "MERCH-001", Email: "ops@example.com", Country: "NL"}},
+	})
+
+	assert.Equal(t, bulkimport.StatusValid, results[0].Status)
+	assert.Empty(t, starter.started)
+}
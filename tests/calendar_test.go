@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"temporal-customer-onboarding/calendar"
+	"temporal-customer-onboarding/shared"
+)
+
+func TestMerchantLocation(t *testing.T) {
+	assert.Equal(t, "Europe/Amsterdam", calendar.MerchantLocation(shared.MerchantInfo{Country: "NL"}).String())
+	assert.Equal(t, "Europe/Lisbon", calendar.MerchantLocation(shared.MerchantInfo{Country: "NL", Timezone: "Europe/Lisbon"}).String())
+	assert.Equal(t, "UTC", calendar.MerchantLocation(shared.MerchantInfo{Country: "ZZ"}).String())
+}
+
+func TestNextSendTime(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+	window := shared.DefaultSendWindow
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{
+			name: "inside window on a business day",
+			at:   time.Date(2026, 3, 10, 11, 30, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 10, 11, 30, 0, 0, amsterdam),
+		},
+		{
+			name: "before window",
+			at:   time.Date(2026, 3, 10, 3, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 10, 9, 0, 0, 0, amsterdam),
+		},
+		{
+			name: "after window rolls to next day",
+			at:   time.Date(2026, 3, 10, 18, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 11, 9, 0, 0, 0, amsterdam),
+		},
+		{
+			name: "weekend defers to Monday",
+			at:   time.Date(2026, 3, 14, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 16, 9, 0, 0, 0, amsterdam),
+		},
+		{
+			name: "King's Day defers to the next business day",
+			at:   time.Date(2026, 4, 27, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 4, 28, 9, 0, 0, 0, amsterdam),
+		},
+		{
+			name: "Christmas weekend",
+			at:   time.Date(2026, 12, 24, 20, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 12, 28, 9, 0, 0, 0, amsterdam),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := calendar.NextSendTime(tt.at.UTC(), amsterdam, window, "NL")
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+func TestPreviousSendTime(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+	window := shared.DefaultSendWindow
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want time.Time
+	}{
+		{
+			name: "inside window on a business day",
+			at:   time.Date(2026, 3, 10, 11, 30, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 10, 11, 30, 0, 0, amsterdam),
+		},
+		{
+			name: "after window closes the same day",
+			at:   time.Date(2026, 3, 10, 18, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 10, 16, 59, 0, 0, amsterdam),
+		},
+		{
+			name: "deadline just after window closes",
+			at:   time.Date(2026, 3, 10, 17, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 10, 16, 59, 0, 0, amsterdam),
+		},
+		{
+			name: "before window goes back to the previous business day",
+			at:   time.Date(2026, 3, 10, 3, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 9, 16, 59, 0, 0, amsterdam),
+		},
+		{
+			name: "weekend goes back to Friday",
+			at:   time.Date(2026, 3, 15, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 3, 13, 16, 59, 0, 0, amsterdam),
+		},
+		{
+			name: "King's Day goes back past the weekend",
+			at:   time.Date(2026, 4, 27, 12, 0, 0, 0, amsterdam),
+			want: time.Date(2026, 4, 24, 16, 59, 0, 0, amsterdam),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := calendar.PreviousSendTime(tt.at.UTC(), amsterdam, window, "NL")
+			assert.True(t, ok)
+			assert.True(t, tt.want.Equal(got), "want %v, got %v", tt.want, got)
+		})
+	}
+}
+
+// TestHolidayCalendarCoversUpcomingDeadlines fails once holidays.json no
+// longer covers onboardings starting in the next few months. Add the next
+// year's public holidays when it does.
+func TestHolidayCalendarCoversUpcomingDeadlines(t *testing.T) {
+	horizon := time.Now().AddDate(0, 3, 0).Add(shared.DeadlineDay90)
+	for _, country := range calendar.HolidayCountries() {
+		assert.True(t, calendar.CoversHolidays(country, horizon),
+			"calendar/holidays.json has no %d holidays for %s", horizon.Year(), country)
+	}
+}
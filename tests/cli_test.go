@@ -0,0 +1,202 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"temporal-customer-onboarding/cli"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+// fakeService records CLI calls and answers from canned state.
+type fakeService struct {
+	started    []shared.OnboardingRequest
+	submitted  map[string]string
+	extensions map[string]shared.DeadlineExtension
+	resends    map[string]shared.ReminderResend
+	statuses   map[string]shared.OnboardingStatusResponse
+	results    map[string]shared.OnboardingResult
+	running    map[string]bool
+	listed     []onboarding.ListFilter
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		submitted:  make(map[string]string),
+		extensions: make(map[string]shared.DeadlineExtension),
+		resends:    make(map[string]shared.ReminderResend),
+		statuses:   make(map[string]shared.OnboardingStatusResponse),
+		results:    make(map[string]shared.OnboardingResult),
+		running:    make(map[string]bool),
+	}
+}
+
+func (f *fakeService) known(merchantID string) error {
+	if _, ok := f.statuses[merchantID]; !ok {
+		return onboarding.ErrNotFound
+	}
+	return nil
+}
+
+func (f *fakeService) Start(_ context.Context, req shared.OnboardingRequest) (onboarding.Execution, error) {
+	if _, ok := f.statuses[req.Merchant.MerchantID]; ok {
+		return onboarding.Execution{}, onboarding.ErrAlreadyStarted
+	}
+	f.started = append(f.started, req)
+	return onboarding.Execution{WorkflowID: shared.OnboardingWorkflowID(req.Merchant.MerchantID), RunID: "run-1"}, nil
+}
+
+func (f *fakeService) SubmitDocument(_ context.Context, merchantID, documentID string) error {
+	f.submitted[merchantID] = documentID
+	return f.known(merchantID)
+}
+
+func (f *fakeService) Status(_ context.Context, merchantID string) (shared.OnboardingStatusResponse, error) {
+	return f.statuses[merchantID], f.known(merchantID)
+}
+
+func (f *fakeService) ExtendDeadline(_ context.Context, merchantID string, ext shared.DeadlineExtension) error {
+	f.extensions[merchantID] = ext
+	return f.known(merchantID)
+}
+
+func (f *fakeService) ResendReminder(_ context.Context, merchantID string, resend shared.ReminderResend) error {
+	f.resends[merchantID] = resend
+	return f.known(merchantID)
+}
+
+func (f *fakeService) Cancel(_ context.Context, merchantID string) error {
+	return f.known(merchantID)
+}
+
+func (f *fakeService) Result(_ context.Context, merchantID string, wait bool) (shared.OnboardingResult, error) {
+	if err := f.known(merchantID); err != nil {
+		return shared.OnboardingResult{}, err
+	}
+	if f.running[merchantID] && !wait {
+		return shared.OnboardingResult{}, onboarding.ErrNotCompleted
+	}
+	return f.results[merchantID], nil
+}
+
+func (f *fakeService) List(_ context.Context, filter onboarding.ListFilter) ([]onboarding.Summary, error) {
+	f.listed = append(f.listed, filter)
+	var summaries []onboarding.Summary
+	for id := range f.statuses {
+		summaries = append(summaries, onboarding.Summary{
+			MerchantID: id,
+			WorkflowID: shared.OnboardingWorkflowID(id),
+			Status:     "Running",
+		})
+	}
+	return summaries, nil
+}
+
+func runCLI(svc cli.Service, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	app := &cli.CLI{Service: svc, Stdout: &stdout, Stderr: &stderr}
+	code := app.Run(context.Background(), args)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestCLI_StartMergesFileAndFlags(t *testing.T) {
+	svc := newFakeService()
+	path := filepath.Join(t.TempDir(), "merchant.json")
+	body := `{"merchant":{"merchantId":"MERCH-042","name":"Acme","email":"old@acme.example","country":"NL"},"reminderWindow":{"startHour":8,"endHour":12}}`
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o644))
+
+	code, stdout, _ := runCLI(svc, "start", "-file", path, "-email", "new@acme.example", "--output", "json")
+	require.Equal(t, cli.ExitOK, code)
+
+	require.Len(t, svc.started, 1)
+	req := svc.started[0]
+	assert.Equal(t, "MERCH-042", req.Merchant.MerchantID)
+	assert.Equal(t, "Acme", req.Merchant.Name)
+	assert.Equal(t, "new@acme.example", req.Merchant.Email)
+	assert.Equal(t, &shared.SendWindow{StartHour: 8, EndHour: 12}, req.ReminderWindow)
+
+	var exec onboarding.Execution
+	require.NoError(t, json.Unmarshal([]byte(stdout), &exec))
+	assert.Equal(t, "onboard-merchant-MERCH-042", exec.WorkflowID)
+}
+
+func TestCLI_ExitCodes(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{Status: shared.StatusRemindersActive}
+	svc.running["MERCH-001"] = true
+
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"no command", nil, cli.ExitUsage},
+		{"unknown command", []string{"frobnicate"}, cli.ExitUsage},
+		{"missing merchant", []string{"status"}, cli.ExitUsage},
+		{"bad output format", []string{"status", "MERCH-001", "-output", "yaml"}, cli.ExitUsage},
+		{"start without merchant", []string{"start", "-name", "Acme"}, cli.ExitUsage},
+		{"already started", []string{"start", "-merchant-id", "MERCH-001"}, cli.ExitAlreadyStarted},
+		{"unknown merchant", []string{"status", "MERCH-404"}, cli.ExitNotFound},
+		{"still running", []string{"result", "MERCH-001"}, cli.ExitNotCompleted},
+		{"wait for result", []string{"result", "MERCH-001", "-wait"}, cli.ExitOK},
+		{"status", []string{"status", "MERCH-001"}, cli.ExitOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, _ := runCLI(svc, tt.args...)
+			assert.Equal(t, tt.want, code)
+		})
+	}
+}
+
+func TestCLI_FlagsAfterMerchantID(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{}
+
+	code, stdout, _ := runCLI(svc, "extend", "MERCH-001", "-days", "14", "-reason", "documents in the post")
+	assert.Equal(t, cli.ExitOK, code)
+	assert.Contains(t, stdout, "14-day deadline extension")
+	assert.Equal(t, shared.DeadlineExtension{Days: 14, Reason: "documents in the post"}, svc.extensions["MERCH-001"])
+}
+
+func TestCLI_JSONErrorsOnStderr(t *testing.T) {
+	code, stdout, stderr := runCLI(newFakeService(), "-output", "json", "cancel", "MERCH-404")
+	assert.Equal(t, cli.ExitNotFound, code)
+	assert.Empty(t, stdout)
+
+	var body struct {
+		Error    string `json:"error"`
+		ExitCode int    `json:"exitCode"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(stderr), &body))
+	assert.Equal(t, cli.ExitNotFound, body.ExitCode)
+}
+
+func TestCLI_ImportWritesReport(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-002"] = shared.OnboardingStatusResponse{} // Already onboarding.
+
+	dir := t.TempDir()
+	input := filepath.Join(dir, "merchants.csv")
+	report := filepath.Join(dir, "report.csv")
+	csv := "merchantId,email,country\nMERCH-001,ops@acme.example,NL\nMERCH-002,ops@beta.example,DE\nMERCH-003,not-an-email,FR\n"
+	require.NoError(t, os.WriteFile(input, []byte(csv), 0o644))
+
+	code, stdout, _ := runCLI(svc, "import", input, "-rate", "0", "-report", report)
+	assert.Equal(t, cli.ExitImportErrors, code)
+	assert.Contains(t, stdout, "3 rows: 1 started, 1 skipped, 1 invalid, 0 failed")
+	require.Len(t, svc.started, 1)
+	assert.Equal(t, "MERCH-001", svc.started[0].Merchant.MerchantID)
+
+	data, err := os.ReadFile(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "4,MERCH-003,invalid,,,")
+}
@@ -0,0 +1,134 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_ApprovedWithConditions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	conditions := shared.MerchantConditions{
+		MonthlyVolumeLimit: 2_500_000,
+		EnhancedMonitoring: true,
+		Reason:             "high-risk business category",
+	}
+	env.OnActivity(a.SendReminder, mock.Anything, mock.MatchedBy(func(req shared.ReminderRequest) bool {
+		return req.ReminderType == "onboardingApprovedWithConditions"
+	})).Return("REMIND-001", nil).Once()
+	env.OnActivity(a.ApplyMerchantConditions, mock.Anything, shared.MerchantConditionsRequest{
+		MerchantID: "MERCH-001",
+		Conditions: conditions,
+	}).Return(nil).Once()
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001", Conditions: &conditions}, nil,
+	)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	var result shared.OnboardingResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, shared.OutcomeApprovedWithConditions, result.Outcome)
+	assert.Equal(t, &conditions, result.Conditions)
+	assert.Equal(t, "ONBOARD-MERCH-001-APPROVED-WITH-CONDITIONS", result.ResultCode)
+
+	statusResp := queryStatus(t, env)
+	assert.Equal(t, shared.StatusApprovedWithConditions, statusResp.Status)
+	assert.Equal(t, &conditions, statusResp.Conditions)
+}
+
+func TestIdentityVerificationWorkflow_MergesConditions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := &activities.Activities{}
+
+	env.RegisterActivity(a.ValidateWithSupplier)
+	env.RegisterActivity(a.PerformInternalVerifications)
+
+	env.OnActivity(a.ValidateWithSupplier, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{
+			Passed:     true,
+			Conditions: &shared.MerchantConditions{MonthlyVolumeLimit: 5_000_000, EnhancedMonitoring: true, Reason: "low-confidence match"},
+		}, nil,
+	)
+	env.OnActivity(a.PerformInternalVerifications, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{
+			Passed:     true,
+			Conditions: &shared.MerchantConditions{MonthlyVolumeLimit: 1_000_000, NoThirdPartyPayouts: true, Reason: "new business"},
+		}, nil,
+	)
+
+	env.ExecuteWorkflow(workflows.IdentityVerificationWorkflow, "MERCH-001", "123456-LC", "")
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	var result shared.VerificationResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.True(t, result.Passed)
+	require.NotNil(t, result.Conditions)
+	assert.Equal(t, shared.MerchantConditions{
+		MonthlyVolumeLimit:  1_000_000,
+		NoThirdPartyPayouts: true,
+		EnhancedMonitoring:  true,
+		Reason:              "low-confidence match; new business",
+	}, *result.Conditions)
+}
+
+func TestOnboardingWorkflow_ConditionsNotAppliedKeepRestrictions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	conditions := shared.MerchantConditions{EnhancedMonitoring: true, Reason: "high-risk business category"}
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.ApplyMerchantConditions, mock.Anything, mock.Anything).Return(
+		temporal.NewNonRetryableApplicationError("platform rejected conditions", "ConditionsRejected", nil),
+	).Once()
+	env.OnActivity(a.LiftRestrictions, mock.Anything, mock.Anything).Return(nil).Never()
+	var alerts []shared.OpsAlert
+	env.OnActivity(a.NotifyOps, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, alert shared.OpsAlert) error {
+			alerts = append(alerts, alert)
+			return nil
+		},
+	)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001", Conditions: &conditions}, nil,
+	)
+
+	// After payouts were held on Day 75.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 76*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.ErrorContains(t, env.GetWorkflowError(), "failed to apply approval conditions")
+	env.AssertExpectations(t)
+	require.Len(t, alerts, 1)
+	assert.Contains(t, alerts[0].Reason, "could not be applied")
+	assert.Equal(t, shared.RestrictionPayoutHold, queryRestriction(t, env))
+}
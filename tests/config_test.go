@@ -0,0 +1,206 @@
+package tests
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func envMap(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func TestConfig_FileWithEnvOverrides(t *testing.T) {
+	keyFile := writeFile(t, "payload.key", base64.StdEncoding.EncodeToString(make([]byte, 32))+"\n")
+	path := writeFile(t, "onboarding.yaml", `
+temporal:
+  hostPort: temporal.internal:7233
+  namespace: onboarding-staging
+  identity: worker-1
+  tls:
+    enabled: true
+    serverName: temporal.internal
+  dataConverter:
+    encryptionKeyFile: `+keyFile+`
+`)
+
+	cfg, err := config.LoadFrom(path, envMap(map[string]string{
+		"TEMPORAL_NAMESPACE": "onboarding-prod",
+		"TEMPORAL_API_KEY":   "secret",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "temporal.internal:7233", cfg.Temporal.HostPort)
+	assert.Equal(t, "onboarding-prod", cfg.Temporal.Namespace)
+	assert.Equal(t, config.ExporterNone, cfg.Tracing.ExporterName())
+
+	opts, err := cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.Equal(t, "onboarding-prod", opts.Namespace)
+	assert.Equal(t, "worker-1", opts.Identity)
+	assert.NotNil(t, opts.Credentials)
+	require.NotNil(t, opts.ConnectionOptions.TLS)
+	assert.Equal(t, "temporal.internal", opts.ConnectionOptions.TLS.ServerName)
+	assert.NotNil(t, opts.DataConverter)
+}
+
+func TestConfig_Defaults(t *testing.T) {
+	cfg, err := config.LoadFrom("", envMap(nil))
+	require.NoError(t, err)
+	opts, err := cfg.ClientOptions()
+	require.NoError(t, err)
+	assert.Equal(t, "localhost:7233", opts.HostPort)
+	assert.Equal(t, "default", opts.Namespace)
+	assert.Nil(t, opts.ConnectionOptions.TLS)
+	assert.Nil(t, opts.DataConverter)
+}
+
+func TestConfig_RejectsInvalidSettings(t *testing.T) {
+	_, err := config.LoadFrom(writeFile(t, "typo.yaml", "temporal:\n  hostport: localhost:7233\n"), envMap(nil))
+	assert.ErrorContains(t, err, "field hostport not found")
+
+	_, err = config.LoadFrom("", envMap(map[string]string{
+		"TEMPORAL_ADDRESS":              "localhost",
+		"TEMPORAL_TLS_CLIENT_CERT_PATH": "/missing/client.pem",
+	}))
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "temporal.hostPort")
+	assert.ErrorContains(t, err, "certFile and keyFile must be set together")
+	assert.ErrorContains(t, err, "temporal.tls.certFile")
+
+	_, err = config.LoadFrom("", envMap(map[string]string{"TEMPORAL_TLS": "yes please"}))
+	assert.ErrorContains(t, err, "TEMPORAL_TLS")
+
+	_, err = config.LoadFrom("", envMap(map[string]string{"OTEL_TRACES_EXPORTER": "jaeger", "OTEL_EXPORTER_OTLP_ENDPOINT": "collector:4318"}))
+	assert.ErrorContains(t, err, "tracing.exporter")
+	assert.ErrorContains(t, err, "tracing.endpoint")
+
+	_, err = config.LoadFrom("", envMap(map[string]string{"ONBOARDING_LOG_FORMAT": "logfmt", "ONBOARDING_LOG_LEVEL": "verbose"}))
+	assert.ErrorContains(t, err, "logging.format")
+	assert.ErrorContains(t, err, "logging.level")
+}
+
+func TestEncryptionCodec_RoundTripAndPlaintextPassthrough(t *testing.T) {
+	codec, err := config.NewEncryptionCodec(make([]byte, 32), "k1")
+	require.NoError(t, err)
+	dc := converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codec)
+
+	payload, err := dc.ToPayload(shared.DeadlineExtension{Days: 7, Reason: "documents in the post"})
+	require.NoError(t, err)
+	assert.Equal(t, "binary/encrypted", string(payload.Metadata["encoding"]))
+	assert.NotContains(t, string(payload.Data), "documents in the post")
+
+	var ext shared.DeadlineExtension
+	require.NoError(t, dc.FromPayload(payload, &ext))
+	assert.Equal(t, 7, ext.Days)
+
+	// Histories written before encryption was turned on still decode.
+	plain, err := converter.GetDefaultDataConverter().ToPayload("MERCH-001")
+	require.NoError(t, err)
+	var merchantID string
+	require.NoError(t, dc.FromPayload(plain, &merchantID))
+	assert.Equal(t, "MERCH-001", merchantID)
+
+	other, err := config.NewEncryptionCodec(make([]byte, 32), "k2")
+	require.NoError(t, err)
+	_, err = other.Decode([]*commonpb.Payload{payload})
+	assert.ErrorContains(t, err, `key "k1"`)
+}
+
+func TestConfig_TaskQueuesAndWorkerOptions(t *testing.T) {
+	path := writeFile(t, "onboarding.yaml", `
+taskQueues:
+  activity: onboarding-activities-eu
+workers:
+  activity:
+    maxConcurrentActivities: 20
+    taskQueueActivitiesPerSecond: 5
+    stopTimeout: 30s
+  workflow:
+    workflowPollers: 4
+`)
+	cfg, err := config.LoadFrom(path, envMap(map[string]string{
+		"ONBOARDING_WORKFLOW_TASK_QUEUE":   "onboarding-workflows-eu",
+		"ONBOARDING_ACTIVITY_METRICS_ADDR": ":9091",
+		"ONBOARDING_ACTIVITY_HEALTH_ADDR":  ":9091",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, config.TaskQueues{Workflow: "onboarding-workflows-eu", Activity: "onboarding-activities-eu"}, cfg.TaskQueues)
+	assert.Equal(t, ":9091", cfg.Workers.Activity.MetricsAddress)
+	assert.Empty(t, cfg.Workers.Workflow.MetricsAddress)
+	assert.Equal(t, ":9091", cfg.Workers.Activity.HealthAddress)
+
+	opts := cfg.Workers.Activity.Options()
+	assert.Equal(t, 20, opts.MaxConcurrentActivityExecutionSize)
+	assert.Equal(t, 5.0, opts.TaskQueueActivitiesPerSecond)
+	assert.Equal(t, 30*time.Second, opts.WorkerStopTimeout)
+	assert.Equal(t, 4, cfg.Workers.Workflow.Options().MaxConcurrentWorkflowTaskPollers)
+
+	cfg, err = config.LoadFrom("", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, config.TaskQueues{Workflow: shared.OnboardingWorkflowTaskQueue, Activity: shared.ActivityTaskQueue}, cfg.TaskQueues)
+
+	_, err = config.LoadFrom(writeFile(t, "bad.yaml", `
+workers:
+  activity:
+    maxConcurrentActivities: -1
+  workflow:
+    workflowPollers: 1
+    metricsAddress: "9090"
+    healthAddress: "localhost"
+`), envMap(nil))
+	assert.ErrorContains(t, err, "workers.activity.maxConcurrentActivities must not be negative")
+	assert.ErrorContains(t, err, "workers.workflow.workflowPollers must be at least 2")
+	assert.ErrorContains(t, err, "workers.workflow.metricsAddress")
+	assert.ErrorContains(t, err, "workers.workflow.healthAddress")
+}
+
+func TestOnboardingWorkflow_UsesActivityTaskQueueFromRequest(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	queues := make(map[string]bool)
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		queues[info.TaskQueue] = true
+	})
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, "MERCH-001", "123456789", "onboarding-activities-eu").Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 24*time.Hour)
+
+	req := defaultOnboardingRequest()
+	req.ActivityTaskQueue = "onboarding-activities-eu"
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, map[string]bool{"onboarding-activities-eu": true}, queues)
+}
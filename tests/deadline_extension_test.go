@@ -0,0 +1,70 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_ExtendDeadline(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, mock.Anything).Return(nil)
+
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, "MERCH-001").Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	).Once()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalExtendDeadline, shared.DeadlineExtension{Days: 14, Reason: "documents in the post"})
+		env.SignalWorkflow(shared.SignalExtendDeadline, shared.DeadlineExtension{Days: -3})
+	}, 50*24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		statusResp := queryStatus(t, env)
+		assert.True(t, statusResp.Deadline.Equal(startTime.Add(shared.DeadlineDay90+14*24*time.Hour)))
+		assert.Equal(t, 53, statusResp.DaysRemaining)
+	}, 50*24*time.Hour+time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, shared.DeadlineDay90+14*24*time.Hour, disabledAt)
+}
+
+func TestOnboardingWorkflow_CancelStopsWithoutDisabling(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.RegisterDelayedCallback(env.CancelWorkflow, 40*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	assert.True(t, temporal.IsCanceledError(err))
+	env.AssertNotCalled(t, "DisablePayments", mock.Anything, mock.Anything)
+	assert.Equal(t, shared.StatusCancelled, queryStatus(t, env).Status)
+}
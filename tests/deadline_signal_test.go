@@ -21,7 +21,7 @@ func TestOnboardingWorkflow_SignalDuringDeadlinePhase(t *testing.T) {
 	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
 
 	// Mock child workflow (KYC) - we expect this to run if the signal is processed!
-	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything).Return(
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		shared.VerificationResult{
 			Passed:         true,
 			VerificationID: "KYC-MERCH-001",
@@ -43,9 +43,9 @@ func TestOnboardingWorkflow_SignalDuringDeadlinePhase(t *testing.T) {
 	assert.NoError(t, env.GetWorkflowError())
 
 	// We expect the workflow to SUCCEED because we sent the document before Day 90.
-	var result string
+	var result shared.OnboardingResult
 	env.GetWorkflowResult(&result)
-	assert.Equal(t, "ONBOARD-MERCH-001-APPROVED", result, "Workflow should have approved the user, but got: %s", result)
+	assert.Equal(t, shared.OutcomeApproved, result.Outcome, "Workflow should have approved the user, but got: %s", result.Outcome)
 
 	// Additional Check: Verify TIMING
 	// The signal was sent at Day 70.
@@ -0,0 +1,188 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/webhooks"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_HardBounceEscalatesAndFallsBack(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	var channels []string
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			channels = append(channels, req.Channel)
+			return "REMIND-MERCH-001-" + req.ReminderType, nil
+		},
+	)
+	env.OnActivity(a.EscalateToAccountManager, mock.Anything, mock.MatchedBy(func(req shared.EscalationRequest) bool {
+		return req.AccountManagerEmail == "am@example.com" && req.FallbackChannel == shared.ChannelSMS
+	})).Return(nil).Once()
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	// The Day 30 email hard-bounces.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDeliveryReceipt, shared.DeliveryReceipt{
+			MerchantID: "MERCH-001",
+			ReminderID: "REMIND-MERCH-001-day30",
+			Channel:    shared.ChannelEmail,
+			Status:     shared.DeliveryBounced,
+			BounceType: shared.BounceHard,
+			Reason:     "mailbox does not exist",
+		})
+	}, time.Hour*24*31)
+
+	env.RegisterDelayedCallback(func() {
+		result, err := env.QueryWorkflow(shared.QueryOnboardingStatus)
+		assert.NoError(t, err)
+		var statusResp shared.OnboardingStatusResponse
+		assert.NoError(t, result.Get(&statusResp))
+		assert.True(t, statusResp.Escalated)
+		if assert.Len(t, statusResp.Deliveries, 1) {
+			assert.Equal(t, shared.DeliveryBounced, statusResp.Deliveries[0].Status)
+		}
+	}, time.Hour*24*32)
+
+	req := defaultOnboardingRequest()
+	req.Merchant.Phone = "+31600000000"
+	req.Merchant.AccountManagerEmail = "am@example.com"
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	// Day 30 by email, Day 30 re-sent by SMS, then every later reminder by SMS.
+	assert.Equal(t, shared.ChannelEmail, channels[0])
+	assert.Len(t, channels, len(shared.ReminderSchedule)+1)
+	for _, channel := range channels[1:] {
+		assert.Equal(t, shared.ChannelSMS, channel)
+	}
+}
+
+type recordingSignaler struct {
+	workflowIDs []string
+	receipts    []shared.DeliveryReceipt
+	err         error
+}
+
+func (s *recordingSignaler) SignalWorkflow(_ context.Context, workflowID, _, signalName string, arg interface{}) error {
+	if s.err != nil {
+		return s.err
+	}
+	if signalName == shared.SignalDeliveryReceipt {
+		s.workflowIDs = append(s.workflowIDs, workflowID)
+		s.receipts = append(s.receipts, arg.(shared.DeliveryReceipt))
+	}
+	return nil
+}
+
+const receiptSecret = "test-secret"
+
+func signedReceipt(body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhooks/delivery", strings.NewReader(body))
+	r.Header.Set(webhooks.SignatureHeader, webhooks.Sign([]byte(receiptSecret), []byte(body)))
+	return r
+}
+
+func TestReceiptHandler_SignalsBatch(t *testing.T) {
+	signaler := &recordingSignaler{}
+	handler := webhooks.NewReceiptHandler(signaler, receiptSecret)
+
+	body := `[
+		{"merchantId": "MERCH-001", "reminderId": "REMIND-MERCH-001-day30", "channel": "email", "status": "DELIVERED"},
+		{"merchantId": "MERCH-002", "reminderId": "REMIND-MERCH-002-day30", "channel": "email", "status": "BOUNCED", "bounceType": "hard"}
+	]`
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, signedReceipt(body))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, []string{"onboard-merchant-MERCH-001", "onboard-merchant-MERCH-002"}, signaler.workflowIDs)
+	assert.True(t, signaler.receipts[1].IsHardBounce())
+}
+
+func TestReceiptHandler_RejectsInvalidReceipt(t *testing.T) {
+	handler := webhooks.NewReceiptHandler(&recordingSignaler{}, receiptSecret)
+
+	rec := httptest.NewRecorder()
+	body := `{"merchantId": "MERCH-001", "status": "OPENED"}`
+	handler.ServeHTTP(rec, signedReceipt(body))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestReceiptHandler_DropsReceiptsForFinishedWorkflows(t *testing.T) {
+	handler := webhooks.NewReceiptHandler(&recordingSignaler{err: serviceerror.NewNotFound("workflow not found")}, receiptSecret)
+
+	rec := httptest.NewRecorder()
+	body := `{"merchantId": "MERCH-001", "reminderId": "REMIND-MERCH-001-day30", "status": "DELIVERED"}`
+	handler.ServeHTTP(rec, signedReceipt(body))
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestReceiptHandler_RejectsBadSignature(t *testing.T) {
+	signaler := &recordingSignaler{}
+	body := `{"merchantId": "MERCH-001", "reminderId": "REMIND-MERCH-001-day30", "status": "DELIVERED"}`
+
+	for name, handler := range map[string]*webhooks.ReceiptHandler{
+		"wrong secret": webhooks.NewReceiptHandler(signaler, "other-secret"),
+		"no secret":    webhooks.NewReceiptHandler(signaler, ""),
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, signedReceipt(body))
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+
+	rec := httptest.NewRecorder()
+	unsigned := httptest.NewRequest(http.MethodPost, "/webhooks/delivery", strings.NewReader(body))
+	webhooks.NewReceiptHandler(signaler, receiptSecret).ServeHTTP(rec, unsigned)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, signaler.workflowIDs)
+}
+
+func TestOnboardingWorkflow_HardBounceWithoutAccountManagerAlertsOps(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	env.OnActivity(a.EscalateToAccountManager, mock.Anything, mock.Anything).Return(nil).Never()
+	env.OnActivity(a.NotifyOps, mock.Anything, mock.MatchedBy(func(alert shared.OpsAlert) bool {
+		return alert.Outcome == shared.StatusRemindersActive && strings.Contains(alert.Reason, "no account manager")
+	})).Return(nil).Once()
+	env.OnActivity(a.NotifyOps, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDeliveryReceipt, shared.DeliveryReceipt{
+			MerchantID: "MERCH-001",
+			ReminderID: "REMIND-MERCH-001-day30",
+			Channel:    shared.ChannelEmail,
+			Status:     shared.DeliveryBounced,
+			BounceType: shared.BounceHard,
+		})
+	}, time.Hour*24*31)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+
+	"temporal-customer-onboarding/health"
+)
+
+func probe(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var body struct {
+		Status string `json:"status"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	return rec.Code, body.Status
+}
+
+func TestHealth_ReadyOnlyBetweenStartAndDrain(t *testing.T) {
+	checker := health.NewChecker()
+	h := checker.Handler()
+
+	code, status := probe(t, h, "/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "starting", status)
+	code, _ = probe(t, h, "/livez")
+	assert.Equal(t, http.StatusOK, code)
+
+	checker.SetReady()
+	code, status = probe(t, h, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ready", status)
+
+	// Readiness fails as soon as draining starts, while the worker stops.
+	var duringStop int
+	checker.Drain(func() {
+		duringStop, _ = probe(t, h, "/readyz")
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, http.StatusServiceUnavailable, duringStop)
+	code, status = probe(t, h, "/livez")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "draining", status)
+}
+
+func TestHealth_CountsInFlightActivities(t *testing.T) {
+	checker := health.NewChecker()
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: []interceptor.WorkerInterceptor{checker.Interceptor()}})
+
+	env.RegisterActivity(func(ctx context.Context) (int64, error) {
+		return checker.InFlight(), nil
+	})
+	val, err := env.ExecuteActivity("func1")
+	require.NoError(t, err)
+
+	var during int64
+	require.NoError(t, val.Get(&during))
+	assert.Equal(t, int64(1), during)
+	assert.Equal(t, int64(0), checker.InFlight())
+}
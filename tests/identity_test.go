@@ -36,7 +36,7 @@ func TestIdentityVerificationWorkflow_HappyPath(t *testing.T) {
 		}, nil,
 	)
 
-	env.ExecuteWorkflow(workflows.IdentityVerificationWorkflow, "MERCH-001", "123456789")
+	env.ExecuteWorkflow(workflows.IdentityVerificationWorkflow, "MERCH-001", "123456789", "")
 
 	assert.True(t, env.IsWorkflowCompleted())
 	assert.NoError(t, env.GetWorkflowError())
@@ -60,7 +60,7 @@ func TestIdentityVerificationWorkflow_SupplierRejection(t *testing.T) {
 		assert.AnError,
 	)
 
-	env.ExecuteWorkflow(workflows.IdentityVerificationWorkflow, "MERCH-001", "ABC123")
+	env.ExecuteWorkflow(workflows.IdentityVerificationWorkflow, "MERCH-001", "ABC123", "")
 
 	assert.True(t, env.IsWorkflowCompleted())
 	assert.NoError(t, env.GetWorkflowError())
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	tlog "go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/logging"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestLogging_RecordsCarryMerchantContext(t *testing.T) {
+	var buf bytes.Buffer
+	testSuite := &testsuite.WorkflowTestSuite{}
+	testSuite.SetLogger(tlog.NewStructuredLogger(logging.New(&buf, config.Logging{})))
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{
+		Interceptors: []interceptor.WorkerInterceptor{logging.Interceptor().(interceptor.WorkerInterceptor)},
+	})
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: shared.OnboardingWorkflowID("MERCH-001")})
+	a := registerMockActivities(env)
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	records := make(map[string]map[string]any)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record), line)
+		records[record["msg"].(string)] = record
+	}
+
+	// Workers add WorkflowID and RunID to workflow loggers; the test
+	// environment doesn't.
+	started := records["Onboarding workflow started"]
+	require.NotNil(t, started)
+	assert.Equal(t, "MERCH-001", started["merchantId"])
+
+	// PublishStatusEvent runs for real; mocked activities skip interceptors.
+	published := records["Publishing status event"]
+	require.NotNil(t, published)
+	assert.Equal(t, "MERCH-001", published["merchantId"])
+	assert.Equal(t, shared.OnboardingWorkflowID("MERCH-001"), published["WorkflowID"])
+	assert.Equal(t, "PublishStatusEvent", published["ActivityType"])
+	assert.EqualValues(t, 1, published["Attempt"])
+}
@@ -0,0 +1,91 @@
+package tests
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/metrics"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// scrape returns the metric lines named name whose labels include every
+// label, e.g. `country="NL"`.
+func scrape(t *testing.T, prom *metrics.Prometheus, name string, labels ...string) []string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	prom.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+
+	var lines []string
+outer:
+	for _, line := range strings.Split(rec.Body.String(), "\n") {
+		if !strings.HasPrefix(line, name+"{") {
+			continue
+		}
+		for _, label := range labels {
+			if !strings.Contains(line, label) {
+				continue outer
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestMetrics_OnboardingBusinessMetrics(t *testing.T) {
+	prom := metrics.NewPrometheus()
+	testSuite := &testsuite.WorkflowTestSuite{}
+	testSuite.SetMetricsHandler(prom.Handler())
+
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 3*24*time.Hour)
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	actEnv := testSuite.NewTestActivityEnvironment()
+	actEnv.RegisterActivity(&activities.Activities{})
+	_, err := actEnv.ExecuteActivity((&activities.Activities{}).SendReminder, shared.ReminderRequest{
+		MerchantID: "MERCH-001", ReminderType: "day7", Channel: shared.ChannelSMS,
+	})
+	require.NoError(t, err)
+
+	_, err = actEnv.ExecuteActivity((&activities.Activities{}).ValidateWithSupplier, shared.DocumentUpload{
+		MerchantID: "MERCH-001", DocumentID: "ABC",
+	})
+	require.Error(t, err)
+
+	// Reporting is periodic; Close flushes.
+	require.NoError(t, prom.Close())
+
+	assert.Len(t, scrape(t, prom, "onboarding_started_total", `country="NL"`), 1)
+	assert.Len(t, scrape(t, prom, "onboarding_completed_total", `country="NL"`, `outcome="APPROVED"`), 1)
+	assert.Len(t, scrape(t, prom, "onboarding_kyc_attempts_total", `result="passed"`), 1)
+	assert.Len(t, scrape(t, prom, "onboarding_reminders_sent_total", `channel="sms"`, `reminder_type="day7"`), 1)
+	assert.Len(t, scrape(t, prom, "kyc_supplier_requests_total", `result="rejected"`), 1)
+
+	// Three days to submit lands between the 1- and 3-day buckets.
+	assert.Contains(t, scrape(t, prom, "onboarding_document_submission_latency_seconds_bucket", `le="86400"`)[0], "} 0")
+	assert.Contains(t, scrape(t, prom, "onboarding_document_submission_latency_seconds_bucket", `le="259200"`)[0], "} 1")
+	assert.Empty(t, scrape(t, prom, "onboarding_document_submission_latency_seconds_bucket", `le="0.005"`))
+	// Request latencies keep their own buckets.
+	assert.NotEmpty(t, scrape(t, prom, "kyc_supplier_latency_seconds_bucket", `le="0.005"`))
+	assert.Empty(t, scrape(t, prom, "kyc_supplier_latency_seconds_bucket", `le="86400"`))
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_MigratedMerchantKeepsTimeline(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	var reminders []string
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			reminders = append(reminders, req.ReminderType)
+			return "REMIND-001", nil
+		},
+	)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, mock.Anything).Return(nil)
+
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, "MERCH-001").Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	).Once()
+
+	// First payment 50 days before the migration.
+	req := defaultOnboardingRequest()
+	firstPayment := startTime.Add(-50 * 24 * time.Hour)
+	req.FirstPaymentAt = &firstPayment
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, 40*24*time.Hour, disabledAt)
+	assert.Equal(t, []string{"day60", "day83", "day87", "day89"}, reminders, "day30 was the legacy system's to send")
+
+	var result shared.OnboardingResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.True(t, result.StartedAt.Equal(firstPayment))
+}
+
+func TestOnboardingWorkflow_PreSubmittedDocumentGoesStraightToKYC(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.MatchedBy(func(req shared.ReminderRequest) bool {
+		return req.ReminderType == "onboardingApproved"
+	})).Return("REMIND-001", nil).Once()
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, "MERCH-001", "123456789", "").Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	).Once()
+
+	req := defaultOnboardingRequest()
+	req.DocumentID = "123456789"
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+
+	var result shared.OnboardingResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, shared.OutcomeApproved, result.Outcome)
+	assert.NotNil(t, result.DocumentSubmittedAt)
+	assert.Equal(t, []string{"onboardingApproved"}, result.RemindersSent)
+}
@@ -0,0 +1,74 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+)
+
+func TestService_StartRejectsSecondOnboarding(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("ExecuteWorkflow", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		nil, serviceerror.NewWorkflowExecutionAlreadyStarted("already started", "", ""),
+	)
+	svc := onboarding.NewService(c)
+
+	_, err := svc.Start(context.Background(), defaultOnboardingRequest())
+	assert.ErrorIs(t, err, onboarding.ErrAlreadyStarted)
+
+	_, err = svc.Start(context.Background(), shared.OnboardingRequest{})
+	assert.ErrorIs(t, err, onboarding.ErrInvalidRequest)
+}
+
+func TestService_ResultWithoutWaitReportsRunning(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("DescribeWorkflowExecution", mock.Anything, "onboard-merchant-MERCH-001", "").Return(
+		&workflowservice.DescribeWorkflowExecutionResponse{
+			WorkflowExecutionInfo: &workflow.WorkflowExecutionInfo{Status: enums.WORKFLOW_EXECUTION_STATUS_RUNNING},
+		}, nil,
+	)
+	c.On("DescribeWorkflowExecution", mock.Anything, "onboard-merchant-MERCH-404", "").Return(
+		nil, serviceerror.NewNotFound("workflow not found"),
+	)
+	svc := onboarding.NewService(c)
+
+	_, err := svc.Result(context.Background(), "MERCH-001", false)
+	assert.ErrorIs(t, err, onboarding.ErrNotCompleted)
+
+	_, err = svc.Result(context.Background(), "MERCH-404", false)
+	assert.ErrorIs(t, err, onboarding.ErrNotFound)
+}
+
+func TestService_ListFiltersByStatus(t *testing.T) {
+	c := &mocks.Client{}
+	c.On("ListWorkflow", mock.Anything, mock.MatchedBy(func(req *workflowservice.ListWorkflowExecutionsRequest) bool {
+		return req.Query == "WorkflowType = 'OnboardingWorkflow' AND ExecutionStatus = 'Running'"
+	})).Return(&workflowservice.ListWorkflowExecutionsResponse{
+		Executions: []*workflow.WorkflowExecutionInfo{{
+			Execution: &commonpb.WorkflowExecution{WorkflowId: "onboard-merchant-MERCH-001", RunId: "run-1"},
+			Status:    enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+		}},
+	}, nil)
+	svc := onboarding.NewService(c)
+
+	summaries, err := svc.List(context.Background(), onboarding.ListFilter{Status: "running"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	assert.Equal(t, "MERCH-001", summaries[0].MerchantID)
+	assert.Equal(t, "Running", summaries[0].Status)
+
+	_, err = svc.List(context.Background(), onboarding.ListFilter{Status: "Running' OR 1=1"})
+	assert.ErrorIs(t, err, onboarding.ErrInvalidRequest)
+}
@@ -1,12 +1,19 @@
 package tests
 
 import (
+	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
 	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
 
 	"temporal-customer-onboarding/activities"
 	"temporal-customer-onboarding/shared"
@@ -25,6 +32,11 @@ func defaultOnboardingRequest() shared.OnboardingRequest {
 	}
 }
 
+// onboardingStartTime is a Saturday, 11:00 in Amsterdam, chosen so that every
+// scheduled reminder falls on a Dutch business day inside the send window.
+// Tests asserting reminder timing start the workflow here.
+var onboardingStartTime = time.Date(2026, 1, 10, 10, 0, 0, 0, time.UTC)
+
 func registerMockActivities(env *testsuite.TestWorkflowEnvironment) *activities.Activities {
 	a := &activities.Activities{}
 	env.RegisterActivity(a)
@@ -40,7 +52,7 @@ func TestOnboardingWorkflow_HappyPath(t *testing.T) {
 	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
 
 	// Mock child workflow — KYC passes.
-	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything).Return(
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		shared.VerificationResult{
 			Passed:         true,
 			VerificationID: "KYC-MERCH-001",
@@ -59,9 +71,10 @@ func TestOnboardingWorkflow_HappyPath(t *testing.T) {
 	assert.True(t, env.IsWorkflowCompleted())
 	assert.NoError(t, env.GetWorkflowError())
 
-	var result string
+	var result shared.OnboardingResult
 	assert.NoError(t, env.GetWorkflowResult(&result))
-	assert.Equal(t, "ONBOARD-MERCH-001-APPROVED", result)
+	assert.Equal(t, shared.OutcomeApproved, result.Outcome)
+	assert.Equal(t, "KYC-MERCH-001", result.VerificationID)
 }
 
 func TestOnboardingWorkflow_Timeout_DisablesPayments(t *testing.T) {
@@ -80,9 +93,10 @@ func TestOnboardingWorkflow_Timeout_DisablesPayments(t *testing.T) {
 	assert.True(t, env.IsWorkflowCompleted())
 	assert.NoError(t, env.GetWorkflowError())
 
-	var result string
+	var result shared.OnboardingResult
 	assert.NoError(t, env.GetWorkflowResult(&result))
-	assert.Equal(t, "ONBOARD-MERCH-001-PAYMENTS-DISABLED", result)
+	assert.Equal(t, shared.OutcomePaymentsDisabled, result.Outcome)
+	assert.Equal(t, []string{shared.ReasonDocumentsNotSubmitted}, result.ReasonCodes)
 }
 
 func TestOnboardingWorkflow_KYCRejection(t *testing.T) {
@@ -93,7 +107,7 @@ func TestOnboardingWorkflow_KYCRejection(t *testing.T) {
 	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
 
 	// Mock child workflow — KYC fails.
-	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything).Return(
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
 		shared.VerificationResult{
 			Passed:         false,
 			VerificationID: "KYC-FAIL-MERCH-001",
@@ -112,9 +126,9 @@ func TestOnboardingWorkflow_KYCRejection(t *testing.T) {
 	assert.True(t, env.IsWorkflowCompleted())
 	assert.NoError(t, env.GetWorkflowError())
 
-	var result string
+	var result shared.OnboardingResult
 	assert.NoError(t, env.GetWorkflowResult(&result))
-	assert.Equal(t, "ONBOARD-MERCH-001-KYC-REJECTED", result)
+	assert.Equal(t, shared.OutcomeKYCRejected, result.Outcome)
 }
 
 func TestOnboardingWorkflow_QueryStatus(t *testing.T) {
@@ -143,3 +157,217 @@ func TestOnboardingWorkflow_QueryStatus(t *testing.T) {
 
 	assert.True(t, env.IsWorkflowCompleted())
 }
+
+func TestOnboardingWorkflow_RemindersCarryIdempotencyKeys(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	var keys []string
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			keys = append(keys, req.IdempotencyKey)
+			return "REMIND-001", nil
+		},
+	)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.SetStartTime(onboardingStartTime)
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: "onboard-merchant-MERCH-001"})
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	if assert.Len(t, keys, len(shared.ReminderSchedule)) {
+		assert.True(t, strings.HasPrefix(keys[0], "onboard-merchant-MERCH-001/"))
+		assert.True(t, strings.HasSuffix(keys[0], "/day30"))
+		assert.True(t, strings.HasSuffix(keys[1], "/day60"))
+	}
+}
+
+func TestOnboardingWorkflow_FinalWarningsBeforeDeadline(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	type sentReminder struct {
+		day     int
+		urgency string
+	}
+	var sent []sentReminder
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, args converter.EncodedValues) {
+		if info.ActivityType.Name != "SendReminder" {
+			return
+		}
+		var req shared.ReminderRequest
+		assert.NoError(t, args.Get(&req))
+		day := int(env.Now().Sub(startTime).Hours() / 24)
+		sent = append(sent, sentReminder{day, req.Urgency})
+	})
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).
+		Return("REMIND-001", nil).
+		After(48 * time.Hour) // A very slow email provider.
+
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []sentReminder{
+		{30, shared.UrgencyStandard},
+		{60, shared.UrgencyStandard},
+		{83, shared.UrgencyFinal},
+		{87, shared.UrgencyFinal},
+		{89, shared.UrgencyFinal},
+	}, sent)
+
+	// Reminder latency must not push the deadline back.
+	assert.Equal(t, shared.DeadlineDay90, disabledAt)
+}
+
+func TestOnboardingWorkflow_LegacyExecutionsKeepSequentialReminders(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	// Started before the reminder timeline existed.
+	env.OnGetVersion("reminder-timeline", workflow.DefaultVersion, 3).Return(workflow.DefaultVersion)
+
+	var sentDays []int
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		if info.ActivityType.Name == "SendReminder" {
+			sentDays = append(sentDays, int(env.Now().Sub(startTime).Hours()/24))
+		}
+	})
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).
+		Return("REMIND-001", nil).
+		After(48 * time.Hour)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil).Never()
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, mock.Anything).Return(nil).Never()
+
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	// Day 60 waits 30 days after the slow Day 30 reminder was sent, and
+	// there are neither final warnings nor restrictions.
+	assert.Equal(t, []int{30, 62}, sentDays)
+	assert.Equal(t, shared.DeadlineDay90, disabledAt)
+}
+
+func TestOnboardingWorkflow_RemindersRespectLocalBusinessHours(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	// First payment at 03:00 Amsterdam time on Thursday 1 January 2026.
+	env.SetStartTime(time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC))
+
+	var sentAt []time.Time
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		if info.ActivityType.Name == "SendReminder" {
+			sentAt = append(sentAt, env.Now())
+		}
+	})
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+
+	var disabledAt time.Time
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now()
+			return nil
+		},
+	)
+
+	req := defaultOnboardingRequest()
+	req.ReminderWindow = &shared.SendWindow{StartHour: 10, EndHour: 16}
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+	assert.Len(t, sentAt, len(shared.ReminderSchedule))
+	for _, ts := range sentAt {
+		local := ts.In(amsterdam)
+		assert.GreaterOrEqual(t, local.Hour(), 10, "sent at %v", local)
+		assert.Less(t, local.Hour(), 16, "sent at %v", local)
+		assert.NotEqual(t, time.Saturday, local.Weekday(), "sent at %v", local)
+		assert.NotEqual(t, time.Sunday, local.Weekday(), "sent at %v", local)
+	}
+
+	// Day 30 is Saturday 31 January → deferred to Monday 2 February, 10:00.
+	assert.Equal(t, time.Date(2026, 2, 2, 10, 0, 0, 0, amsterdam), sentAt[0].In(amsterdam))
+
+	// The legal deadline is never shifted.
+	assert.Equal(t, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC).Add(shared.DeadlineDay90), disabledAt.UTC())
+}
+
+func TestOnboardingWorkflow_FinalWarningBeforeWeekendDeadline(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+	// Sent in the last slot before the deadline: Friday 1 May, 16:59.
+	assert.Equal(t, time.Date(2026, 5, 1, 16, 59, 0, 0, amsterdam),
+		finalWarningBeforeWeekendDeadline(t, 3).In(amsterdam))
+}
+
+// Executions pinned to earlier timeline versions keep sending the warning
+// when the last window opens.
+func TestOnboardingWorkflow_FinalWarningBeforeWeekendDeadline_PinnedTimeline(t *testing.T) {
+	amsterdam, err := time.LoadLocation("Europe/Amsterdam")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 5, 1, 9, 0, 0, 0, amsterdam),
+		finalWarningBeforeWeekendDeadline(t, 2).In(amsterdam))
+}
+
+// finalWarningBeforeWeekendDeadline runs an onboarding that expires on a
+// Sunday under the given reminder-timeline version and returns when the last
+// reminder was sent.
+func finalWarningBeforeWeekendDeadline(t *testing.T, timelineVersion workflow.Version) time.Time {
+	t.Helper()
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.OnGetVersion("reminder-timeline", workflow.DefaultVersion, 3).Return(timelineVersion)
+
+	// Day 89 is Saturday 2 May 2026 and the deadline Sunday 3 May, so the
+	// Day 89 warning can't wait for Monday.
+	start := time.Date(2026, 2, 2, 10, 0, 0, 0, time.UTC)
+	env.SetStartTime(start)
+
+	var sentAt []time.Time
+	env.SetOnActivityStartedListener(func(info *activity.Info, _ context.Context, _ converter.EncodedValues) {
+		if info.ActivityType.Name == "SendReminder" {
+			sentAt = append(sentAt, env.Now())
+		}
+	})
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	require.Len(t, sentAt, len(shared.ReminderSchedule))
+	return sentAt[len(sentAt)-1]
+}
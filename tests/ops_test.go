@@ -0,0 +1,208 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// chatReceiver is a local stand-in for a chat incoming webhook.
+type chatReceiver struct {
+	mu       sync.Mutex
+	messages []map[string]interface{}
+}
+
+func (r *chatReceiver) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	var msg map[string]interface{}
+	if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mu.Lock()
+	r.messages = append(r.messages, msg)
+	r.mu.Unlock()
+}
+
+func (r *chatReceiver) texts() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var texts []string
+	for _, msg := range r.messages {
+		texts = append(texts, msg["text"].(string))
+	}
+	return texts
+}
+
+func TestNotifyOps_PostsAlertWithWorkflowLink(t *testing.T) {
+	receiver := &chatReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Ops: activities.NewOpsAlerter(server.URL, "http://localhost:8233")}
+	env.RegisterActivity(a.NotifyOps)
+
+	_, err := env.ExecuteActivity(a.NotifyOps, shared.OpsAlert{
+		MerchantID:   "MERCH-001",
+		MerchantName: "Test Store",
+		Country:      "NL",
+		Outcome:      shared.StatusPaymentsDisabled,
+		Reason:       "deadline expired",
+		Namespace:    "default",
+		WorkflowID:   "onboard-merchant-MERCH-001",
+		RunID:        "run-1",
+	})
+	assert.NoError(t, err)
+
+	if assert.Len(t, receiver.messages, 1) {
+		body, _ := json.Marshal(receiver.messages[0])
+		assert.Contains(t, string(body), "PAYMENTS_DISABLED")
+		assert.Contains(t, string(body), "http://localhost:8233/namespaces/default/workflows/onboard-merchant-MERCH-001/run-1/history")
+	}
+}
+
+func TestOpsAlerter_AggregatesBurst(t *testing.T) {
+	receiver := &chatReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	alerter := activities.NewOpsAlerter(server.URL, "")
+	alerter.MaxPerWindow = 2
+	alerter.Window = 100 * time.Millisecond
+
+	for _, id := range []string{"M-1", "M-2", "M-3", "M-4", "M-5"} {
+		err := alerter.Notify(context.Background(), shared.OpsAlert{MerchantID: id, Outcome: shared.StatusPaymentsDisabled})
+		assert.NoError(t, err)
+	}
+
+	// Two individual alerts immediately, then one digest for the other three.
+	assert.Len(t, receiver.texts(), 2)
+	assert.Eventually(t, func() bool { return len(receiver.texts()) == 3 }, time.Second, 10*time.Millisecond)
+
+	digest := receiver.texts()[2]
+	assert.True(t, strings.HasPrefix(digest, ":rotating_light: 3 more onboarding alerts"), digest)
+}
+
+// A failed post is retried by the activity; it must not use up the window.
+func TestOpsAlerter_FailedPostDoesNotUseWindow(t *testing.T) {
+	receiver := &chatReceiver{}
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(rw, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		receiver.ServeHTTP(rw, req)
+	}))
+	defer server.Close()
+
+	alerter := activities.NewOpsAlerter(server.URL, "")
+	alerter.MaxPerWindow = 1
+	alerter.Window = time.Hour
+
+	alert := shared.OpsAlert{MerchantID: "M-1", Outcome: shared.StatusPaymentsDisabled}
+	assert.Error(t, alerter.Notify(context.Background(), alert))
+	assert.NoError(t, alerter.Notify(context.Background(), alert))
+
+	// The retry is posted on its own rather than held for the digest.
+	if assert.Len(t, receiver.texts(), 1) {
+		assert.Contains(t, receiver.texts()[0], "M-1")
+	}
+}
+
+func TestOpsAlerter_QueuedAlertsSurviveRestart(t *testing.T) {
+	receiver := &chatReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+	queuePath := filepath.Join(t.TempDir(), "ops-alerts.jsonl")
+
+	// The chat webhook goes down after the first alert, before the first
+	// process shuts down.
+	flaky := httptest.NewServer(&chatReceiver{})
+	first := activities.NewOpsAlerter(flaky.URL, "")
+	first.MaxPerWindow = 1
+	first.Window = time.Hour
+	require.NoError(t, first.OpenQueue(queuePath))
+	assert.NoError(t, first.Notify(context.Background(), shared.OpsAlert{MerchantID: "M-1", Outcome: shared.StatusPaymentsDisabled}))
+	flaky.Close()
+	for _, id := range []string{"M-2", "M-3"} {
+		err := first.Notify(context.Background(), shared.OpsAlert{MerchantID: id, Outcome: shared.StatusPaymentsDisabled})
+		assert.NoError(t, err)
+	}
+	assert.Error(t, first.Close())
+
+	// The next process posts the queued alerts on shutdown.
+	second := activities.NewOpsAlerter(server.URL, "")
+	second.Window = time.Hour
+	require.NoError(t, second.OpenQueue(queuePath))
+	assert.NoError(t, second.Close())
+
+	if assert.Len(t, receiver.texts(), 1) {
+		assert.True(t, strings.HasPrefix(receiver.texts()[0], ":rotating_light: 2 more onboarding alerts"), receiver.texts()[0])
+	}
+	data, err := os.ReadFile(queuePath)
+	require.NoError(t, err)
+	assert.Empty(t, data)
+}
+
+func TestOnboardingWorkflow_KYCRejectionNotifiesOps(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.NotifyOps, mock.Anything, mock.MatchedBy(func(alert shared.OpsAlert) bool {
+		return alert.MerchantID == "MERCH-001" &&
+			alert.Outcome == shared.StatusRejected &&
+			alert.Reason == "Supplier rejected identity document"
+	})).Return(nil).Once()
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: false, Details: "Supplier rejected identity document"}, nil,
+	)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "ABC123")
+	}, time.Millisecond*100)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestOnboardingWorkflow_DisablePaymentsNotifiesOps(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.NotifyOps, mock.Anything, mock.MatchedBy(func(alert shared.OpsAlert) bool {
+		return alert.Outcome == shared.StatusPaymentsDisabled && alert.WorkflowID != ""
+	})).Return(nil).Once()
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
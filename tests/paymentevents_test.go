@@ -0,0 +1,302 @@
+package tests
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/paymentevents"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// fakeSignalWithStarter mimics the server's SignalWithStart semantics: the
+// first call for a workflow ID starts it, later calls only signal it.
+type fakeSignalWithStarter struct {
+	mu      sync.Mutex
+	starts  map[string]shared.OnboardingRequest
+	signals map[string][]shared.PaymentEvent
+	closed  map[string]bool
+}
+
+func newFakeSignalWithStarter() *fakeSignalWithStarter {
+	return &fakeSignalWithStarter{
+		starts:  make(map[string]shared.OnboardingRequest),
+		signals: make(map[string][]shared.PaymentEvent),
+		closed:  make(map[string]bool),
+	}
+}
+
+func (f *fakeSignalWithStarter) SignalWithStartWorkflow(_ context.Context, workflowID string, signalName string, signalArg interface{},
+	options client.StartWorkflowOptions, _ interface{}, workflowArgs ...interface{}) (client.WorkflowRun, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed[workflowID] && options.WorkflowIDReusePolicy == enums.WORKFLOW_ID_REUSE_POLICY_REJECT_DUPLICATE {
+		return nil, serviceerror.NewWorkflowExecutionAlreadyStarted("workflow already completed", "", "")
+	}
+	if _, running := f.starts[workflowID]; !running {
+		f.starts[workflowID] = workflowArgs[0].(shared.OnboardingRequest)
+	}
+	if signalName == shared.SignalPaymentReceived {
+		f.signals[workflowID] = append(f.signals[workflowID], signalArg.(shared.PaymentEvent))
+	}
+	return nil, nil
+}
+
+func paymentEvent(eventID, merchantID string) shared.PaymentEvent {
+	return shared.PaymentEvent{
+		EventID:  eventID,
+		Merchant: shared.MerchantInfo{MerchantID: merchantID, Email: "test@example.com", Country: "NL"},
+		Amount:   1250,
+		Currency: "EUR",
+	}
+}
+
+func TestConsumer_CollapsesEventsPerMerchant(t *testing.T) {
+	temporalClient := newFakeSignalWithStarter()
+	consumer := paymentevents.NewConsumer(temporalClient)
+
+	src := paymentevents.NewMemorySource(
+		paymentEvent("pay-1", "MERCH-001"),
+		paymentEvent("pay-1", "MERCH-001"), // Redelivery.
+		paymentEvent("pay-2", "MERCH-002"),
+		paymentEvent("pay-3", "MERCH-001"),
+	)
+	src.Close()
+
+	require.NoError(t, consumer.Run(context.Background(), src))
+
+	assert.Len(t, temporalClient.starts, 2, "one onboarding per merchant")
+	assert.Equal(t, "MERCH-001", temporalClient.starts["onboard-merchant-MERCH-001"].Merchant.MerchantID)
+	assert.Len(t, temporalClient.signals["onboard-merchant-MERCH-001"], 3)
+	assert.Len(t, temporalClient.signals["onboard-merchant-MERCH-002"], 1)
+}
+
+func TestConsumer_SkipsCompletedOnboarding(t *testing.T) {
+	temporalClient := newFakeSignalWithStarter()
+	temporalClient.closed["onboard-merchant-MERCH-001"] = true
+	consumer := paymentevents.NewConsumer(temporalClient)
+
+	err := consumer.Handle(context.Background(), paymentEvent("pay-9", "MERCH-001"))
+	assert.ErrorIs(t, err, paymentevents.ErrAlreadyOnboarded)
+	assert.Empty(t, temporalClient.starts)
+}
+
+func TestFileSource_ReadsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	content := `{"eventId":"pay-1","merchant":{"merchantId":"MERCH-001"},"amount":100,"currency":"EUR"}
+
+not json
+{"eventId":"pay-2","merchant":{"merchantId":"MERCH-002"},"amount":200,"currency":"GBP"}`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+
+	src, err := paymentevents.OpenFileSource(path, false, "")
+	require.NoError(t, err)
+	defer src.Close()
+	ctx := context.Background()
+
+	event, err := src.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "pay-1", event.EventID)
+
+	_, err = src.Next(ctx)
+	assert.ErrorContains(t, err, "invalid payment event")
+
+	event, err = src.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "MERCH-002", event.Merchant.MerchantID)
+
+	_, err = src.Next(ctx)
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestFileSource_FollowsAppendedEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+
+	src, err := paymentevents.OpenFileSource(path, true, "")
+	require.NoError(t, err)
+	defer src.Close()
+	src.PollInterval = 10 * time.Millisecond
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+		defer f.Close()
+		_, _ = f.WriteString(`{"eventId":"pay-late","merchant":{"merchantId":"MERCH-001"}}` + "\n")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	event, err := src.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "pay-late", event.EventID)
+}
+
+func TestHTTPSource_RespondsOnceEventIsAcked(t *testing.T) {
+	src := paymentevents.NewHTTPSource(1)
+
+	post := func(eventID string) int {
+		rec := httptest.NewRecorder()
+		body := `{"eventId":"` + eventID + `","merchant":{"merchantId":"MERCH-001"},"amount":100,"currency":"EUR"}`
+		src.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/events", strings.NewReader(body)))
+		return rec.Code
+	}
+	codes := make(chan int, 2)
+	go func() { codes <- post("pay-1") }()
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		codes <- post("pay-2")
+	}()
+
+	// pay-1 fills the buffer, so pay-2 is turned away.
+	assert.Equal(t, http.StatusServiceUnavailable, <-codes, "buffer full")
+
+	ctx := context.Background()
+	event, err := src.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "pay-1", event.EventID)
+	select {
+	case code := <-codes:
+		t.Fatalf("responded %d before the event was acked", code)
+	case <-time.After(20 * time.Millisecond):
+	}
+	src.Ack(nil)
+	assert.Equal(t, http.StatusNoContent, <-codes)
+
+	// A failed delivery tells the sender to retry.
+	go func() { codes <- post("pay-3") }()
+	_, err = src.Next(ctx)
+	require.NoError(t, err)
+	src.Ack(context.Canceled)
+	assert.Equal(t, http.StatusServiceUnavailable, <-codes)
+}
+
+func TestFileSource_ResumesAfterLastAckedEvent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	checkpoint := filepath.Join(dir, "events.offset")
+	content := `{"eventId":"pay-1","merchant":{"merchantId":"MERCH-001"}}
+{"eventId":"pay-2","merchant":{"merchantId":"MERCH-001"}}
+{"eventId":"pay-3","merchant":{"merchantId":"MERCH-001"}}
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	ctx := context.Background()
+
+	src, err := paymentevents.OpenFileSource(path, false, checkpoint)
+	require.NoError(t, err)
+	_, err = src.Next(ctx)
+	require.NoError(t, err)
+	src.Ack(nil)
+	_, err = src.Next(ctx) // pay-2 is read but never delivered.
+	require.NoError(t, err)
+	require.NoError(t, src.Close())
+
+	src, err = paymentevents.OpenFileSource(path, false, checkpoint)
+	require.NoError(t, err)
+	defer src.Close()
+	event, err := src.Next(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "pay-2", event.EventID)
+}
+
+// flakySignalWithStarter fails the first failures calls.
+type flakySignalWithStarter struct {
+	*fakeSignalWithStarter
+	failures int
+	calls    int
+}
+
+func (f *flakySignalWithStarter) SignalWithStartWorkflow(ctx context.Context, workflowID string, signalName string, signalArg interface{},
+	options client.StartWorkflowOptions, workflow interface{}, workflowArgs ...interface{}) (client.WorkflowRun, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, serviceerror.NewUnavailable("frontend unavailable")
+	}
+	return f.fakeSignalWithStarter.SignalWithStartWorkflow(ctx, workflowID, signalName, signalArg, options, workflow, workflowArgs...)
+}
+
+// ackRecorder records the acks a Source receives.
+type ackRecorder struct {
+	paymentevents.Source
+	acks []error
+}
+
+func (r *ackRecorder) Ack(err error) {
+	r.acks = append(r.acks, err)
+	r.Source.Ack(err)
+}
+
+func TestConsumer_RetriesUntilDelivered(t *testing.T) {
+	temporalClient := &flakySignalWithStarter{fakeSignalWithStarter: newFakeSignalWithStarter(), failures: 2}
+	consumer := paymentevents.NewConsumer(temporalClient)
+	consumer.InitialBackoff = time.Millisecond
+
+	mem := paymentevents.NewMemorySource(paymentEvent("pay-1", "MERCH-001"))
+	mem.Close()
+	src := &ackRecorder{Source: mem}
+
+	require.NoError(t, consumer.Run(context.Background(), src))
+	assert.Equal(t, 3, temporalClient.calls)
+	assert.Len(t, temporalClient.signals["onboard-merchant-MERCH-001"], 1)
+	assert.Equal(t, []error{nil}, src.acks)
+}
+
+func TestConsumer_LeavesEventUnackedWhenStopped(t *testing.T) {
+	temporalClient := &flakySignalWithStarter{fakeSignalWithStarter: newFakeSignalWithStarter(), failures: 1000}
+	consumer := paymentevents.NewConsumer(temporalClient)
+	consumer.InitialBackoff = time.Millisecond
+
+	src := &ackRecorder{Source: paymentevents.NewMemorySource(paymentEvent("pay-1", "MERCH-001"))}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, consumer.Run(ctx, src), context.DeadlineExceeded)
+	if assert.Len(t, src.acks, 1) {
+		assert.Error(t, src.acks[0])
+	}
+}
+
+func TestOnboardingWorkflow_RecordsPaymentEvents(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalPaymentReceived, paymentEvent("pay-1", "MERCH-001"))
+		env.SignalWorkflow(shared.SignalPaymentReceived, paymentEvent("pay-1", "MERCH-001"))
+		env.SignalWorkflow(shared.SignalPaymentReceived, paymentEvent("pay-2", "MERCH-001"))
+	}, time.Hour)
+
+	env.RegisterDelayedCallback(func() {
+		result, err := env.QueryWorkflow(shared.QueryOnboardingStatus)
+		require.NoError(t, err)
+		var statusResp shared.OnboardingStatusResponse
+		require.NoError(t, result.Get(&statusResp))
+		assert.Equal(t, 2, statusResp.PaymentsSeen)
+	}, 2*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+}
@@ -0,0 +1,183 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/payments"
+	"temporal-customer-onboarding/shared"
+)
+
+// newFakePayments starts a fake payment platform and returns it with a
+// client pointed at it.
+func newFakePayments(t *testing.T, merchantIDs ...string) (*payments.FakeServer, *payments.Client) {
+	fake := payments.NewFakeServer(merchantIDs...)
+	fake.Token = "test-token"
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	client, err := payments.NewClient(payments.Config{BaseURL: server.URL, Token: "test-token"})
+	require.NoError(t, err)
+	return fake, client
+}
+
+func TestDisablePayments_DisablesMerchantOnPlatform(t *testing.T) {
+	fake, client := newFakePayments(t, "MERCH-001")
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Payments: client}
+	env.RegisterActivity(a.DisablePayments)
+
+	_, err := env.ExecuteActivity(a.DisablePayments, "MERCH-001")
+	assert.NoError(t, err)
+
+	merchant, ok := fake.Merchant("MERCH-001")
+	assert.True(t, ok)
+	assert.False(t, merchant.PaymentsEnabled)
+}
+
+func TestDisablePayments_UnknownMerchantIsNonRetryable(t *testing.T) {
+	_, client := newFakePayments(t)
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Payments: client}
+	env.RegisterActivity(a.DisablePayments)
+
+	_, err := env.ExecuteActivity(a.DisablePayments, "MERCH-404")
+
+	var appErr *temporal.ApplicationError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, shared.ErrTypeMerchantNotFound, appErr.Type())
+	assert.True(t, appErr.NonRetryable())
+}
+
+func TestDisablePayments_RateLimitedIsRetryable(t *testing.T) {
+	fake, client := newFakePayments(t, "MERCH-001")
+	fake.FailNext(http.StatusTooManyRequests)
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Payments: client}
+	env.RegisterActivity(a.DisablePayments)
+
+	_, err := env.ExecuteActivity(a.DisablePayments, "MERCH-001")
+
+	var appErr *temporal.ApplicationError
+	require.ErrorAs(t, err, &appErr)
+	assert.False(t, appErr.NonRetryable())
+
+	merchant, _ := fake.Merchant("MERCH-001")
+	assert.True(t, merchant.PaymentsEnabled, "failed request must not change state")
+}
+
+func TestPaymentsClient_ErrorMapping(t *testing.T) {
+	fake, client := newFakePayments(t, "MERCH-001")
+	ctx := context.Background()
+
+	fake.FailNext(http.StatusServiceUnavailable, http.StatusBadRequest)
+
+	var apiErr *payments.APIError
+	err := client.DisablePayments(ctx, "MERCH-001")
+	require.ErrorAs(t, err, &apiErr)
+	assert.True(t, apiErr.Retryable())
+
+	err = client.DisablePayments(ctx, "MERCH-001")
+	require.ErrorAs(t, err, &apiErr)
+	assert.False(t, apiErr.Retryable())
+
+	err = client.DisablePayments(ctx, "MERCH-002")
+	assert.True(t, errors.Is(err, payments.ErrMerchantNotFound))
+}
+
+// A 404 that doesn't name the merchant as unknown came from somewhere other
+// than the merchant API, such as a wrong base URL, and must be retried.
+func TestPaymentsClient_UnrelatedNotFoundIsRetryable(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/payments/", http.StripPrefix("/payments", payments.NewFakeServer("MERCH-001")))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	// Missing the /payments prefix the platform is served under.
+	client, err := payments.NewClient(payments.Config{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	var apiErr *payments.APIError
+	err = client.DisablePayments(context.Background(), "MERCH-001")
+	assert.False(t, errors.Is(err, payments.ErrMerchantNotFound))
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusNotFound, apiErr.StatusCode)
+	assert.True(t, apiErr.Retryable())
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Payments: client}
+	env.RegisterActivity(a.DisablePayments)
+
+	_, err = env.ExecuteActivity(a.DisablePayments, "MERCH-001")
+	var appErr *temporal.ApplicationError
+	require.ErrorAs(t, err, &appErr)
+	assert.False(t, appErr.NonRetryable())
+}
+
+func TestPaymentsClient_RequiresToken(t *testing.T) {
+	fake := payments.NewFakeServer("MERCH-001")
+	fake.Token = "secret"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client, err := payments.NewClient(payments.Config{BaseURL: server.URL, Token: "wrong"})
+	require.NoError(t, err)
+
+	var apiErr *payments.APIError
+	require.ErrorAs(t, client.DisablePayments(context.Background(), "MERCH-001"), &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+}
+
+func TestPaymentsClient_RestrictionsRoundTrip(t *testing.T) {
+	fake, client := newFakePayments(t, "MERCH-001")
+	ctx := context.Background()
+
+	require.NoError(t, client.HoldPayouts(ctx, "MERCH-001"))
+	require.NoError(t, client.CapTransactionVolume(ctx, "MERCH-001", 100_000))
+
+	merchant, _ := fake.Merchant("MERCH-001")
+	assert.True(t, merchant.PayoutsHeld)
+	assert.Equal(t, int64(100_000), merchant.DailyVolumeCap)
+	assert.True(t, merchant.PaymentsEnabled)
+
+	require.NoError(t, client.LiftRestrictions(ctx, "MERCH-001"))
+
+	merchant, err := client.GetMerchant(ctx, "MERCH-001")
+	require.NoError(t, err)
+	assert.Equal(t, payments.Merchant{MerchantID: "MERCH-001", PaymentsEnabled: true}, merchant)
+}
+
+func TestApplyMerchantConditions_SetsConditionsOnPlatform(t *testing.T) {
+	fake, client := newFakePayments(t, "MERCH-001")
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestActivityEnvironment()
+	a := &activities.Activities{Payments: client}
+	env.RegisterActivity(a.ApplyMerchantConditions)
+
+	_, err := env.ExecuteActivity(a.ApplyMerchantConditions, shared.MerchantConditionsRequest{
+		MerchantID: "MERCH-001",
+		Conditions: shared.MerchantConditions{MonthlyVolumeLimit: 5_000_000, NoThirdPartyPayouts: true},
+	})
+	require.NoError(t, err)
+
+	merchant, _ := fake.Merchant("MERCH-001")
+	assert.Equal(t, payments.Conditions{MonthlyVolumeLimit: 5_000_000, ThirdPartyPayoutsBlocked: true}, merchant.Conditions)
+	assert.True(t, merchant.PaymentsEnabled)
+}
@@ -0,0 +1,71 @@
+package tests
+
+import (
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	tlog "go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// historyFiles are the histories in testdata/histories, one per known path
+// through OnboardingWorkflow. The baseline-* ones were produced by the
+// workflow as it was before any change ID existed, the reminder-timeline-2-*
+// ones by executions pinned to reminder-timeline version 2, the others by the
+// current code. They come from a worker run against a stand-in frontend rather than
+// a server; histories from a dev server, exported with
+// `temporal workflow show -w WORKFLOW_ID -o json`, work just as well.
+func historyFiles(t *testing.T) []string {
+	t.Helper()
+	files, err := filepath.Glob("testdata/histories/*.json")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	return files
+}
+
+func newReplayer(t *testing.T, onboarding interface{}) worker.WorkflowReplayer {
+	t.Helper()
+	replayer := worker.NewWorkflowReplayer()
+	replayer.RegisterWorkflowWithOptions(onboarding, workflow.RegisterOptions{Name: "OnboardingWorkflow"})
+	replayer.RegisterWorkflow(workflows.IdentityVerificationWorkflow)
+	return replayer
+}
+
+var quietLogger = tlog.NewStructuredLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+func TestReplay_HistoriesReplayAgainstCurrentCode(t *testing.T) {
+	replayer := newReplayer(t, workflows.OnboardingWorkflow)
+	for _, file := range historyFiles(t) {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			require.NoError(t, replayer.ReplayWorkflowHistoryFromJSONFile(quietLogger, file))
+		})
+	}
+}
+
+// An unguarded change to the timer and activity sequence must fail replay.
+func TestReplay_DetectsUnversionedChange(t *testing.T) {
+	changed := func(ctx workflow.Context, req shared.OnboardingRequest) (shared.OnboardingResult, error) {
+		if err := workflow.Sleep(ctx, time.Hour); err != nil {
+			return shared.OnboardingResult{}, err
+		}
+		return workflows.OnboardingWorkflow(ctx, req)
+	}
+	replayer := newReplayer(t, changed)
+	for _, file := range historyFiles(t) {
+		err := replayer.ReplayWorkflowHistoryFromJSONFile(quietLogger, file)
+		if assert.Error(t, err, file) {
+			// TMPRL1100 marks non-determinism errors.
+			assert.Contains(t, err.Error(), "TMPRL1100", file)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func queryRestriction(t *testing.T, env *testsuite.TestWorkflowEnvironment) shared.RestrictionLevel {
+	result, err := env.QueryWorkflow(shared.QueryOnboardingStatus)
+	assert.NoError(t, err)
+	var statusResp shared.OnboardingStatusResponse
+	assert.NoError(t, result.Get(&statusResp))
+	return statusResp.Restriction
+}
+
+func TestOnboardingWorkflow_GraduatedRestrictions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	var appliedAt []time.Duration
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, "MERCH-001").Return(
+		func(_ context.Context, _ string) error {
+			appliedAt = append(appliedAt, env.Now().Sub(startTime))
+			return nil
+		},
+	).Once()
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, shared.VolumeCapRequest{MerchantID: "MERCH-001", DailyVolumeCap: 100_000}).Return(
+		func(_ context.Context, _ shared.VolumeCapRequest) error {
+			appliedAt = append(appliedAt, env.Now().Sub(startTime))
+			return nil
+		},
+	).Once()
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil).Once()
+
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, shared.RestrictionNone, queryRestriction(t, env))
+	}, 74*24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, shared.RestrictionPayoutHold, queryRestriction(t, env))
+	}, 80*24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, shared.RestrictionVolumeCap, queryRestriction(t, env))
+	}, 86*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, []time.Duration{shared.PayoutHoldDay75, shared.VolumeCapDay85}, appliedAt)
+	assert.Equal(t, shared.RestrictionDisabled, queryRestriction(t, env))
+}
+
+func TestOnboardingWorkflow_HighRiskTierRestrictsEarlier(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, shared.VolumeCapRequest{MerchantID: "MERCH-001", DailyVolumeCap: 50_000}).Return(nil).Once()
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, shared.RestrictionVolumeCap, queryRestriction(t, env))
+	}, 76*24*time.Hour)
+
+	req := defaultOnboardingRequest()
+	req.Merchant.RiskTier = shared.RiskTierHigh
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+}
+
+func TestOnboardingWorkflow_ApprovalLiftsRestrictions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil).Once()
+	env.OnActivity(a.LiftRestrictions, mock.Anything, "MERCH-001").Return(nil).Once()
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+
+	// Documents arrive at Day 80: payouts are already held, the volume cap isn't.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 80*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	env.AssertNotCalled(t, "CapTransactionVolume", mock.Anything, mock.Anything)
+	assert.Equal(t, shared.RestrictionNone, queryRestriction(t, env))
+}
+
+func TestOnboardingWorkflow_ApprovalLiftsRestrictionStillBeingApplied(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	// The payout hold takes two hours to go through.
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil).After(2 * time.Hour).Once()
+	env.OnActivity(a.LiftRestrictions, mock.Anything, "MERCH-001").Return(nil).Once()
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+
+	// Documents arrive, and KYC passes, while the Day 75 hold is in flight.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, shared.PayoutHoldDay75+time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	assert.Equal(t, shared.RestrictionNone, queryRestriction(t, env))
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestOnboardingWorkflow_ResultRecordsHistory(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var result shared.OnboardingResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, shared.OutcomePaymentsDisabled, result.Outcome)
+	assert.Equal(t, "MERCH-001", result.MerchantID)
+	assert.True(t, result.StartedAt.Equal(startTime))
+	assert.Nil(t, result.DocumentSubmittedAt)
+	assert.True(t, result.DecidedAt.Equal(startTime.Add(shared.DeadlineDay90)))
+	assert.Equal(t, []string{"day30", "day60", "day83", "day87", "day89"}, result.RemindersSent)
+	assert.Equal(t, []shared.RestrictionLevel{
+		shared.RestrictionPayoutHold,
+		shared.RestrictionVolumeCap,
+		shared.RestrictionDisabled,
+	}, result.RestrictionsApplied)
+	assert.Equal(t, "ONBOARD-MERCH-001-PAYMENTS-DISABLED", result.ResultCode)
+}
+
+func TestOnboardingWorkflow_LegacyExecutionReturnsResultString(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	// An execution started before typed results has no version marker.
+	env.OnGetVersion("typed-onboarding-result", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	var code string
+	require.NoError(t, env.GetWorkflowResult(&code))
+	assert.Equal(t, "ONBOARD-MERCH-001-APPROVED", code)
+
+	// New callers can decode the same payload into OnboardingResult.
+	var result shared.OnboardingResult
+	require.NoError(t, env.GetWorkflowResult(&result))
+	assert.Equal(t, shared.OutcomeApproved, result.Outcome)
+	assert.Equal(t, "MERCH-001", result.MerchantID)
+}
+
+func TestOnboardingResult_DecodesLegacyResultStrings(t *testing.T) {
+	tests := []struct {
+		code       string
+		outcome    shared.Outcome
+		merchantID string
+	}{
+		{"ONBOARD-MERCH-001-APPROVED", shared.OutcomeApproved, "MERCH-001"},
+		{"ONBOARD-MERCH-001-APPROVED-WITH-CONDITIONS", shared.OutcomeApprovedWithConditions, "MERCH-001"},
+		{"ONBOARD-NL-42-KYC-REJECTED", shared.OutcomeKYCRejected, "NL-42"},
+		{"ONBOARD-MERCH-001-PAYMENTS-DISABLED", shared.OutcomePaymentsDisabled, "MERCH-001"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			data, _ := json.Marshal(tt.code)
+			var result shared.OnboardingResult
+			require.NoError(t, json.Unmarshal(data, &result))
+			assert.Equal(t, tt.outcome, result.Outcome)
+			assert.Equal(t, tt.merchantID, result.MerchantID)
+			assert.Equal(t, tt.code, shared.LegacyResultCode(tt.merchantID, tt.outcome))
+		})
+	}
+
+	var result shared.OnboardingResult
+	assert.Error(t, json.Unmarshal([]byte(`"SOMETHING-ELSE"`), &result))
+}
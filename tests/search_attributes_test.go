@@ -0,0 +1,192 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/workflow/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+
+	"temporal-customer-onboarding/api"
+	"temporal-customer-onboarding/cli"
+	"temporal-customer-onboarding/onboarding"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+// indexedAttributes is the merged result of a workflow's search attribute
+// upserts.
+type indexedAttributes struct {
+	merchantID, country, businessType, status string
+	deadline                                  time.Time
+	kycAttempts                               int64
+}
+
+func recordSearchAttributes(env *testsuite.TestWorkflowEnvironment) *indexedAttributes {
+	var got indexedAttributes
+	env.OnUpsertTypedSearchAttributes(mock.Anything).Run(func(args mock.Arguments) {
+		sa := args.Get(0).(temporal.SearchAttributes)
+		for key, dst := range map[temporal.SearchAttributeKeyKeyword]*string{
+			shared.SearchAttrMerchantID:       &got.merchantID,
+			shared.SearchAttrCountry:          &got.country,
+			shared.SearchAttrBusinessType:     &got.businessType,
+			shared.SearchAttrOnboardingStatus: &got.status,
+		} {
+			if v, ok := sa.GetKeyword(key); ok {
+				*dst = v
+			}
+		}
+		if v, ok := sa.GetTime(shared.SearchAttrDeadline); ok {
+			got.deadline = v
+		}
+		if v, ok := sa.GetInt64(shared.SearchAttrKYCAttempts); ok {
+			got.kycAttempts = v
+		}
+	}).Return(nil)
+	return &got
+}
+
+func TestOnboardingWorkflow_IndexesSearchAttributes(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	indexed := recordSearchAttributes(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, indexedAttributes{
+			merchantID:   "MERCH-001",
+			country:      "NL",
+			businessType: "ecommerce",
+			status:       string(shared.StatusRemindersActive),
+			deadline:     onboardingStartTime.Add(shared.DeadlineDay90),
+		}, *indexed)
+
+		env.SignalWorkflow(shared.SignalExtendDeadline, shared.DeadlineExtension{Days: 10})
+	}, 24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.Equal(t, onboardingStartTime.Add(shared.DeadlineDay90+10*24*time.Hour), indexed.deadline)
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 48*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, string(shared.StatusApproved), indexed.status)
+	assert.Equal(t, int64(1), indexed.kycAttempts)
+}
+
+func TestListFilter_Query(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	query, err := onboarding.ListFilter{
+		OnboardingStatus: "awaiting-kyc-documents",
+		Country:          "nl",
+		DeadlineWithin:   10 * 24 * time.Hour,
+	}.Query(now)
+	require.NoError(t, err)
+	assert.Equal(t, "WorkflowType = 'OnboardingWorkflow' AND OnboardingStatus = 'AWAITING_KYC_DOCUMENTS'"+
+		" AND Country = 'NL' AND Deadline < '2026-03-11T12:00:00Z'", query)
+
+	for name, filter := range map[string]onboarding.ListFilter{
+		"unknown onboarding status": {OnboardingStatus: "WAITING"},
+		"quote in business type":    {BusinessType: "retail' OR Country = 'DE"},
+		"negative deadline window":  {DeadlineWithin: -time.Hour},
+	} {
+		_, err := filter.Query(now)
+		assert.ErrorIs(t, err, onboarding.ErrInvalidRequest, name)
+	}
+}
+
+func TestService_ListDecodesSearchAttributes(t *testing.T) {
+	payload := func(v interface{}) *commonpb.Payload {
+		p, err := converter.GetDefaultDataConverter().ToPayload(v)
+		require.NoError(t, err)
+		return p
+	}
+	deadline := time.Date(2026, 4, 10, 10, 0, 0, 0, time.UTC)
+
+	c := &mocks.Client{}
+	c.On("ListWorkflow", mock.Anything, mock.Anything).Return(&workflowservice.ListWorkflowExecutionsResponse{
+		Executions: []*workflow.WorkflowExecutionInfo{{
+			Execution: &commonpb.WorkflowExecution{WorkflowId: "onboard-merchant-MERCH-001", RunId: "run-1"},
+			Status:    enums.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			SearchAttributes: &commonpb.SearchAttributes{IndexedFields: map[string]*commonpb.Payload{
+				"OnboardingStatus": payload("AWAITING_KYC_DOCUMENTS"),
+				"Country":          payload("NL"),
+				"Deadline":         payload(deadline),
+				"KYCAttempts":      payload(int64(0)),
+			}},
+		}},
+	}, nil)
+
+	summaries, err := onboarding.NewService(c).List(context.Background(), onboarding.ListFilter{Country: "NL"})
+	require.NoError(t, err)
+	require.Len(t, summaries, 1)
+	s := summaries[0]
+	assert.Equal(t, shared.StatusRemindersActive, s.OnboardingStatus)
+	assert.Equal(t, "NL", s.Country)
+	require.NotNil(t, s.Deadline)
+	assert.True(t, deadline.Equal(*s.Deadline))
+}
+
+func TestCLI_ListBuildsFilterAndPrintsTable(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{}
+
+	code, stdout, stderr := runCLI(svc, "list", "-country", "NL", "-onboarding-status", "AWAITING_KYC_DOCUMENTS", "-days-left", "10")
+	require.Equal(t, cli.ExitOK, code, stderr)
+	assert.Equal(t, []onboarding.ListFilter{{
+		Country:          "NL",
+		OnboardingStatus: "AWAITING_KYC_DOCUMENTS",
+		DeadlineWithin:   10 * 24 * time.Hour,
+		Limit:            100,
+	}}, svc.listed)
+	assert.Contains(t, stdout, "ONBOARDING STATUS")
+	assert.Contains(t, stdout, "MERCH-001")
+
+	code, _, _ = runCLI(svc, "list", "-days-left", "-1")
+	assert.Equal(t, cli.ExitUsage, code)
+}
+
+func TestAPI_ListOnboardings(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{}
+	server := api.NewServer(svc, apiToken)
+
+	rec := apiRequest(server, http.MethodGet, "/v1/onboardings?country=NL&onboardingStatus=AWAITING_KYC_DOCUMENTS&daysLeft=10&limit=5", "")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var body struct {
+		Onboardings []onboarding.Summary `json:"onboardings"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Onboardings, 1)
+	assert.Equal(t, []onboarding.ListFilter{{
+		Country:          "NL",
+		OnboardingStatus: "AWAITING_KYC_DOCUMENTS",
+		DeadlineWithin:   10 * 24 * time.Hour,
+		Limit:            5,
+	}}, svc.listed)
+
+	rec = apiRequest(server, http.MethodGet, "/v1/onboardings?daysLeft=soon", "")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, api.CodeInvalidRequest, apiErrorCode(t, rec))
+}
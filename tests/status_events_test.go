@@ -0,0 +1,259 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/api"
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+type statusTransition struct {
+	status      shared.OnboardingStatus
+	restriction shared.RestrictionLevel
+}
+
+// recordStatusEvents mocks PublishStatusEvent and returns the transitions
+// published, checking that sequence numbers count up from 1.
+func recordStatusEvents(t *testing.T, env *testsuite.TestWorkflowEnvironment, a *activities.Activities) *[]statusTransition {
+	var published []statusTransition
+	env.OnActivity(a.PublishStatusEvent, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, e shared.StatusEvent) error {
+			assert.Equal(t, len(published)+1, e.Sequence)
+			assert.Equal(t, "MERCH-001", e.MerchantID)
+			published = append(published, statusTransition{e.Status, e.Restriction})
+			return nil
+		},
+	)
+	return &published
+}
+
+func TestOnboardingWorkflow_PublishesStatusTransitions(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	published := recordStatusEvents(t, env, a)
+	env.SetStartTime(onboardingStartTime)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.HoldPayouts, mock.Anything, mock.Anything).Return(nil)
+	env.OnActivity(a.LiftRestrictions, mock.Anything, mock.Anything).Return(nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 80*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Equal(t, []statusTransition{
+		{shared.StatusRemindersActive, shared.RestrictionNone},
+		{shared.StatusRemindersActive, shared.RestrictionPayoutHold},
+		{shared.StatusKYCInProgress, shared.RestrictionPayoutHold},
+		{shared.StatusApproved, shared.RestrictionPayoutHold},
+		{shared.StatusApproved, shared.RestrictionNone},
+	}, *published)
+
+	// The status query names the latest event, so the event stream can
+	// resume from it.
+	encoded, err := env.QueryWorkflow(shared.QueryOnboardingStatus)
+	require.NoError(t, err)
+	var status shared.OnboardingStatusResponse
+	require.NoError(t, encoded.Get(&status))
+	assert.NotEmpty(t, status.RunID)
+	assert.Equal(t, len(*published), status.Sequence)
+}
+
+func TestOnboardingWorkflow_PublishesCancellation(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	published := recordStatusEvents(t, env, a)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.RegisterDelayedCallback(env.CancelWorkflow, 40*24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.Error(t, env.GetWorkflowError())
+	assert.Equal(t, []statusTransition{
+		{shared.StatusRemindersActive, shared.RestrictionNone},
+		{shared.StatusCancelled, shared.RestrictionNone},
+	}, *published)
+}
+
+func TestOnboardingWorkflow_LegacyExecutionPublishesNoStatusEvents(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	published := recordStatusEvents(t, env, a)
+	env.OnGetVersion("status-events", workflow.DefaultVersion, 1).Return(workflow.DefaultVersion)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.DisablePayments, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	assert.Empty(t, *published)
+}
+
+// readEvents reads server-sent events of type "status" until the stream
+// ends or n events have arrived.
+func readEvents(t *testing.T, resp *http.Response, n int) []shared.StatusEvent {
+	var events []shared.StatusEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for len(events) < n && scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		var e shared.StatusEvent
+		require.NoError(t, json.Unmarshal([]byte(data), &e))
+		events = append(events, e)
+	}
+	return events
+}
+
+const statusEventsToken = "worker-secret"
+
+// publishRequest posts a status event to the internal endpoint with token.
+func publishRequest(h http.Handler, token, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/internal/status-events", strings.NewReader(body))
+	r.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, r)
+	return rec
+}
+
+func TestAPI_StreamsStatusEvents(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{
+		Status:      shared.StatusRemindersActive,
+		Restriction: shared.RestrictionNone,
+	}
+	server := api.NewServer(svc, apiToken)
+	server.StatusEventsToken = statusEventsToken
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/onboardings/MERCH-001/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Nothing published yet, so the stream opens with the queried status.
+	initial := readEvents(t, resp, 1)
+	require.Len(t, initial, 1)
+	assert.Equal(t, shared.StatusRemindersActive, initial[0].Status)
+
+	// The activity posts events to the internal endpoint; the repeat of
+	// sequence 1 is dropped and the stream ends after the final status.
+	for _, e := range []shared.StatusEvent{
+		{Sequence: 1, Status: shared.StatusKYCInProgress},
+		{Sequence: 1, Status: shared.StatusKYCInProgress},
+		{Sequence: 2, Status: shared.StatusApproved},
+	} {
+		e.MerchantID, e.RunID, e.Restriction = "MERCH-001", "run-1", shared.RestrictionNone
+		body, err := json.Marshal(e)
+		require.NoError(t, err)
+		rec := publishRequest(server, statusEventsToken, string(body))
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	events := readEvents(t, resp, 10)
+	require.Len(t, events, 2)
+	assert.Equal(t, shared.StatusKYCInProgress, events[0].Status)
+	assert.Equal(t, shared.StatusApproved, events[1].Status)
+	assert.Equal(t, 2, events[1].Sequence)
+}
+
+// A client reconnecting to a server that has seen no events yet gets the
+// current state from the status query. Events it already has, by the query's
+// run and sequence, are not sent again.
+func TestAPI_EventStreamResumesFromLastEventID(t *testing.T) {
+	svc := newFakeService()
+	svc.statuses["MERCH-001"] = shared.OnboardingStatusResponse{
+		Status:      shared.StatusKYCInProgress,
+		Restriction: shared.RestrictionNone,
+		RunID:       "run-1",
+		Sequence:    2,
+	}
+	server := api.NewServer(svc, apiToken)
+	server.StatusEventsToken = statusEventsToken
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	req, err := http.NewRequest(http.MethodGet, ts.URL+"/v1/onboardings/MERCH-001/events", nil)
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+apiToken)
+	req.Header.Set("Last-Event-ID", "2")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	// Sequence 2 is a late repeat of the event the client already has.
+	for _, e := range []shared.StatusEvent{
+		{Sequence: 2, Status: shared.StatusKYCInProgress},
+		{Sequence: 3, Status: shared.StatusApproved},
+	} {
+		e.MerchantID, e.RunID, e.Restriction = "MERCH-001", "run-1", shared.RestrictionNone
+		body, err := json.Marshal(e)
+		require.NoError(t, err)
+		rec := publishRequest(server, statusEventsToken, string(body))
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	}
+
+	events := readEvents(t, resp, 10)
+	require.Len(t, events, 1)
+	assert.Equal(t, shared.StatusApproved, events[0].Status)
+	assert.Equal(t, 3, events[0].Sequence)
+}
+
+func TestAPI_EventStreamForUnknownMerchant(t *testing.T) {
+	server := api.NewServer(newFakeService(), apiToken)
+
+	rec := apiRequest(server, http.MethodGet, "/v1/onboardings/MERCH-404/events", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, api.CodeNotFound, apiErrorCode(t, rec))
+}
+
+func TestAPI_StatusEventsNeedTheirOwnToken(t *testing.T) {
+	body := `{"merchantId":"MERCH-001","runId":"run-1","sequence":1,"status":"APPROVED","restriction":"NONE"}`
+
+	server := api.NewServer(newFakeService(), apiToken)
+	rec := publishRequest(server, apiToken, body)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "disabled without a status-events token")
+
+	server.StatusEventsToken = statusEventsToken
+	rec = publishRequest(server, apiToken, body)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code, "the portal token can't publish events")
+	assert.Equal(t, api.CodeUnauthorized, apiErrorCode(t, rec))
+
+	rec = publishRequest(server, statusEventsToken, body)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
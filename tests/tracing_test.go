@@ -0,0 +1,99 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/worker"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/tracing"
+	"temporal-customer-onboarding/workflows"
+)
+
+func TestTracing_SpansFollowOnboardingIntoKYCActivities(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	clientInterceptors, err := tracing.Interceptors(tp)
+	require.NoError(t, err)
+	var workerInterceptors []interceptor.WorkerInterceptor
+	for _, i := range clientInterceptors {
+		workerInterceptors = append(workerInterceptors, i.(interceptor.WorkerInterceptor))
+	}
+
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	env.SetWorkerOptions(worker.Options{Interceptors: workerInterceptors})
+	env.SetStartWorkflowOptions(client.StartWorkflowOptions{ID: shared.OnboardingWorkflowID("MERCH-001")})
+	a := registerMockActivities(env)
+	env.RegisterWorkflow(workflows.IdentityVerificationWorkflow)
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnActivity(a.ValidateWithSupplier, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "SUP-MERCH-001"}, nil,
+	)
+	// PerformInternalVerifications runs for real: the test environment
+	// skips interceptors for mocked activities.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 24*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, s := range recorder.Ended() {
+		spans[s.Name()] = s
+	}
+	root, ok := spans["RunWorkflow:OnboardingWorkflow"]
+	require.True(t, ok, "no onboarding span")
+	for _, name := range []string{
+		"StartChildWorkflow:IdentityVerificationWorkflow",
+		"RunWorkflow:IdentityVerificationWorkflow",
+		"StartActivity:ValidateWithSupplier",
+		"RunActivity:PerformInternalVerifications",
+	} {
+		span, ok := spans[name]
+		if !assert.True(t, ok, name) {
+			continue
+		}
+		assert.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID(), name)
+	}
+	for _, name := range []string{"RunWorkflow:OnboardingWorkflow", "RunWorkflow:IdentityVerificationWorkflow", "RunActivity:PerformInternalVerifications"} {
+		assert.Contains(t, spans[name].Attributes(), tracing.MerchantIDKey.String("MERCH-001"), name)
+	}
+}
+
+func TestTracing_TransportPropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	var traceparent string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer srv.Close()
+
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "RunActivity:ValidateWithSupplier")
+	defer span.End()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	resp, err := (&http.Client{Transport: tracing.Transport(nil)}).Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+}
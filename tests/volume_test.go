@@ -0,0 +1,215 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+	"temporal-customer-onboarding/workflows"
+)
+
+func queryStatus(t *testing.T, env *testsuite.TestWorkflowEnvironment) shared.OnboardingStatusResponse {
+	result, err := env.QueryWorkflow(shared.QueryOnboardingStatus)
+	require.NoError(t, err)
+	var statusResp shared.OnboardingStatusResponse
+	require.NoError(t, result.Get(&statusResp))
+	return statusResp
+}
+
+func volumePayment(eventID, currency string, amount int64) shared.PaymentEvent {
+	event := paymentEvent(eventID, "MERCH-001")
+	event.Currency = currency
+	event.Amount = amount
+	return event
+}
+
+func TestOnboardingWorkflow_VolumeThresholdTriggersEarlyKYC(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	var urgent []shared.ReminderRequest
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			if req.Urgency == shared.UrgencyUrgent {
+				urgent = append(urgent, req)
+			}
+			return "REMIND-001", nil
+		},
+	)
+	env.OnActivity(a.CapTransactionVolume, mock.Anything, shared.VolumeCapRequest{MerchantID: "MERCH-001", DailyVolumeCap: 20_000}).Return(nil).Once()
+
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, "MERCH-001").Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	).Once()
+
+	// Day 10: two payments take the merchant over the EUR threshold, a GBP
+	// payment doesn't count towards it.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-1", "EUR", 60_000))
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-2", "GBP", 90_000))
+	}, 10*24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		assert.False(t, queryStatus(t, env).EarlyKYCTriggered)
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-3", "EUR", 40_000))
+	}, 10*24*time.Hour+time.Hour)
+	env.RegisterDelayedCallback(func() {
+		statusResp := queryStatus(t, env)
+		assert.True(t, statusResp.EarlyKYCTriggered)
+		assert.Equal(t, map[string]int64{"EUR": 100_000, "GBP": 90_000}, statusResp.ProcessedVolume)
+		assert.Equal(t, shared.RestrictionVolumeCap, statusResp.Restriction)
+		assert.Equal(t, 13, statusResp.DaysRemaining, "14 days from the crossing, an hour ago")
+	}, 10*24*time.Hour+2*time.Hour)
+
+	req := defaultOnboardingRequest()
+	req.VolumePolicy = &shared.VolumePolicy{
+		Thresholds:        map[string]int64{"EUR": 100_000, "GBP": 200_000},
+		EarlyDeadlineDays: 14,
+		DailyVolumeCap:    20_000,
+	}
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, req)
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	require.Len(t, urgent, 1)
+	assert.Equal(t, "volumeThreshold", urgent[0].ReminderType)
+	assert.Equal(t, 24*24*time.Hour+time.Hour, disabledAt)
+	assert.Equal(t, shared.RestrictionDisabled, queryRestriction(t, env))
+}
+
+func TestOnboardingWorkflow_VolumeThresholdIgnoredAfterSubmission(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return("REMIND-001", nil)
+	env.OnWorkflow(workflows.IdentityVerificationWorkflow, mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(
+		shared.VerificationResult{Passed: true, VerificationID: "KYC-MERCH-001"}, nil,
+	).After(time.Hour)
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalDocumentSubmitted, "123456789")
+	}, 24*time.Hour)
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-1", "EUR", 5_000_000))
+	}, 24*time.Hour+time.Minute)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	statusResp := queryStatus(t, env)
+	assert.False(t, statusResp.EarlyKYCTriggered)
+	assert.Equal(t, int64(5_000_000), statusResp.ProcessedVolume["EUR"])
+}
+
+func TestOnboardingWorkflow_VolumeThresholdReminderWaitsForSendWindow(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	var urgentAt []time.Duration
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			if req.Urgency == shared.UrgencyUrgent {
+				urgentAt = append(urgentAt, env.Now().Sub(startTime))
+			}
+			return "REMIND-001", nil
+		},
+	)
+
+	// Tuesday 20 January, 23:00 in Amsterdam.
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-1", "EUR", 5_000_000))
+	}, 10*24*time.Hour+12*time.Hour)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	// Wednesday 21 January, 09:00 in Amsterdam.
+	assert.Equal(t, []time.Duration{10*24*time.Hour + 22*time.Hour}, urgentAt)
+}
+
+func TestOnboardingWorkflow_LegacyExecutionsEnforceEarlyDeadline(t *testing.T) {
+	testSuite := &testsuite.WorkflowTestSuite{}
+	env := testSuite.NewTestWorkflowEnvironment()
+	a := registerMockActivities(env)
+	env.SetStartTime(onboardingStartTime)
+	startTime := env.Now()
+
+	// Started before the reminder timeline existed.
+	env.OnGetVersion("reminder-timeline", workflow.DefaultVersion, 3).Return(workflow.DefaultVersion)
+
+	var urgentAt []time.Duration
+	env.OnActivity(a.SendReminder, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, req shared.ReminderRequest) (string, error) {
+			if req.Urgency == shared.UrgencyUrgent {
+				urgentAt = append(urgentAt, env.Now().Sub(startTime))
+			}
+			return "REMIND-001", nil
+		},
+	)
+	var disabledAt time.Duration
+	env.OnActivity(a.DisablePayments, mock.Anything, "MERCH-001").Return(
+		func(_ context.Context, _ string) error {
+			disabledAt = env.Now().Sub(startTime)
+			return nil
+		},
+	).Once()
+
+	crossing := 10*24*time.Hour + 12*time.Hour
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(shared.SignalPaymentReceived, volumePayment("pay-1", "EUR", 5_000_000))
+	}, crossing)
+
+	env.ExecuteWorkflow(workflows.OnboardingWorkflow, defaultOnboardingRequest())
+
+	assert.True(t, env.IsWorkflowCompleted())
+	assert.NoError(t, env.GetWorkflowError())
+	env.AssertExpectations(t)
+	// The reminder goes out right away, as it always did for these executions.
+	assert.Equal(t, []time.Duration{crossing}, urgentAt)
+	assert.Equal(t, crossing+time.Duration(shared.DefaultVolumePolicy.EarlyDeadlineDays)*24*time.Hour, disabledAt)
+}
+
+func TestVolumePolicy_Validate(t *testing.T) {
+	valid := shared.DefaultVolumePolicy
+	require.NoError(t, valid.Validate())
+
+	tests := map[string]func(p *shared.VolumePolicy){
+		"no thresholds":           func(p *shared.VolumePolicy) { p.Thresholds = nil },
+		"zero threshold":          func(p *shared.VolumePolicy) { p.Thresholds = map[string]int64{"EUR": 0} },
+		"empty currency":          func(p *shared.VolumePolicy) { p.Thresholds = map[string]int64{"": 100} },
+		"zero early deadline":     func(p *shared.VolumePolicy) { p.EarlyDeadlineDays = 0 },
+		"negative early deadline": func(p *shared.VolumePolicy) { p.EarlyDeadlineDays = -1 },
+		"negative volume cap":     func(p *shared.VolumePolicy) { p.DailyVolumeCap = -1 },
+	}
+	for name, mutate := range tests {
+		t.Run(name, func(t *testing.T) {
+			policy := valid
+			mutate(&policy)
+			assert.Error(t, policy.Validate())
+
+			req := defaultOnboardingRequest()
+			req.VolumePolicy = &policy
+			assert.ErrorContains(t, req.Validate(onboardingStartTime), "volumePolicy")
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"temporal-customer-onboarding/workers"
+)
+
+func TestParseRoles(t *testing.T) {
+	for in, want := range map[string][]workers.Role{
+		"workflow":                   {workers.RoleWorkflow},
+		"activity":                   {workers.RoleActivity},
+		"all":                        {workers.RoleWorkflow, workers.RoleActivity},
+		"activity, workflow":         {workers.RoleActivity, workers.RoleWorkflow},
+		"workflow,workflow,activity": {workers.RoleWorkflow, workers.RoleActivity},
+	} {
+		roles, err := workers.ParseRoles(in)
+		require.NoError(t, err, in)
+		assert.Equal(t, want, roles, in)
+	}
+
+	for _, in := range []string{"", "api", "workflow,"} {
+		_, err := workers.ParseRoles(in)
+		assert.Error(t, err, in)
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"temporal-customer-onboarding/workpool"
+)
+
+func TestWorkpool_BoundsConcurrencyAndReportsEachCall(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	var done []int
+	pool := workpool.Pool{
+		Concurrency: 2,
+		Done:        func(i int) { done = append(done, i) },
+	}
+	indexes := []int{0, 2, 3, 5, 8}
+	pool.Run(context.Background(), indexes, func(i int, err error) {
+		assert.NoError(t, err)
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		inFlight.Add(-1)
+	})
+
+	assert.Equal(t, int32(2), peak.Load())
+	assert.ElementsMatch(t, indexes, done)
+}
+
+// Once ctx ends, the remaining calls are told so instead of running.
+func TestWorkpool_CancelledContextReachesEveryCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var mu sync.Mutex
+	errs := make(map[int]error)
+	pool := workpool.Pool{Rate: 1}
+	pool.Run(ctx, []int{0, 1, 2}, func(i int, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		errs[i] = err
+	})
+
+	assert.Len(t, errs, 3)
+	for i, err := range errs {
+		assert.ErrorIs(t, err, context.Canceled, "index %d", i)
+	}
+}
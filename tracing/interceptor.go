@@ -0,0 +1,83 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	otelinterceptor "go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// merchantInterceptor tags the current span with the merchant ID, read from
+// the business workflow ID so no payload needs decoding.
+type merchantInterceptor struct {
+	interceptor.InterceptorBase
+}
+
+func setMerchantID(span trace.Span, workflowID string) {
+	if id := shared.MerchantIDFromWorkflowID(workflowID); id != "" {
+		span.SetAttributes(MerchantIDKey.String(id))
+	}
+}
+
+func (m *merchantInterceptor) InterceptClient(next interceptor.ClientOutboundInterceptor) interceptor.ClientOutboundInterceptor {
+	i := &merchantClientOutbound{}
+	i.Next = next
+	return i
+}
+
+func (m *merchantInterceptor) InterceptWorkflow(ctx workflow.Context, next interceptor.WorkflowInboundInterceptor) interceptor.WorkflowInboundInterceptor {
+	i := &merchantWorkflowInbound{}
+	i.Next = next
+	return i
+}
+
+func (m *merchantInterceptor) InterceptActivity(ctx context.Context, next interceptor.ActivityInboundInterceptor) interceptor.ActivityInboundInterceptor {
+	i := &merchantActivityInbound{}
+	i.Next = next
+	return i
+}
+
+type merchantClientOutbound struct {
+	interceptor.ClientOutboundInterceptorBase
+}
+
+func (m *merchantClientOutbound) ExecuteWorkflow(ctx context.Context, in *interceptor.ClientExecuteWorkflowInput) (client.WorkflowRun, error) {
+	setMerchantID(trace.SpanFromContext(ctx), in.Options.ID)
+	return m.Next.ExecuteWorkflow(ctx, in)
+}
+
+func (m *merchantClientOutbound) SignalWorkflow(ctx context.Context, in *interceptor.ClientSignalWorkflowInput) error {
+	setMerchantID(trace.SpanFromContext(ctx), in.WorkflowID)
+	return m.Next.SignalWorkflow(ctx, in)
+}
+
+func (m *merchantClientOutbound) SignalWithStartWorkflow(ctx context.Context, in *interceptor.ClientSignalWithStartWorkflowInput) (client.WorkflowRun, error) {
+	setMerchantID(trace.SpanFromContext(ctx), in.Options.ID)
+	return m.Next.SignalWithStartWorkflow(ctx, in)
+}
+
+type merchantWorkflowInbound struct {
+	interceptor.WorkflowInboundInterceptorBase
+}
+
+func (m *merchantWorkflowInbound) ExecuteWorkflow(ctx workflow.Context, in *interceptor.ExecuteWorkflowInput) (interface{}, error) {
+	if span, ok := otelinterceptor.SpanFromWorkflowContext(ctx); ok {
+		setMerchantID(span, workflow.GetInfo(ctx).WorkflowExecution.ID)
+	}
+	return m.Next.ExecuteWorkflow(ctx, in)
+}
+
+type merchantActivityInbound struct {
+	interceptor.ActivityInboundInterceptorBase
+}
+
+func (m *merchantActivityInbound) ExecuteActivity(ctx context.Context, in *interceptor.ExecuteActivityInput) (interface{}, error) {
+	setMerchantID(trace.SpanFromContext(ctx), activity.GetInfo(ctx).WorkflowExecution.ID)
+	return m.Next.ExecuteActivity(ctx, in)
+}
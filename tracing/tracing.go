@@ -0,0 +1,134 @@
+// Package tracing sets up OpenTelemetry tracing. Interceptors on the
+// Temporal client carry the trace context through workflow, child workflow
+// and activity headers, so one trace follows an onboarding from the starter
+// through OnboardingWorkflow and IdentityVerificationWorkflow into the
+// supplier activities and their outbound HTTP calls, across workers.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	otelinterceptor "go.temporal.io/sdk/contrib/opentelemetry"
+	"go.temporal.io/sdk/interceptor"
+
+	"temporal-customer-onboarding/config"
+)
+
+// MerchantIDKey is the span attribute holding the merchant ID.
+const MerchantIDKey = attribute.Key("merchant.id")
+
+// Tracing is the process's tracing setup.
+type Tracing struct {
+	// Interceptors trace workflows, child workflows, activities, signals
+	// and queries. Add them to client.Options.Interceptors; workers created
+	// from the client use them too.
+	Interceptors []interceptor.ClientInterceptor
+
+	provider *sdktrace.TracerProvider
+}
+
+// Setup installs the W3C trace context propagator and, unless cfg disables
+// export, a tracer provider for serviceName as the OpenTelemetry globals.
+// With export disabled spans aren't recorded, but the trace context still
+// passes through, so other processes' traces stay connected. Call Shutdown
+// before exiting to flush spans.
+func Setup(ctx context.Context, cfg config.Tracing, serviceName string) (*Tracing, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	t := &Tracing{}
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.ExporterName() {
+	case config.ExporterStdout:
+		// Stderr keeps CLI output parseable.
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stderr), stdouttrace.WithPrettyPrint())
+	case config.ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			endpoint, err := url.JoinPath(cfg.Endpoint, "v1/traces")
+			if err != nil {
+				return nil, fmt.Errorf("tracing.endpoint: %w", err)
+			}
+			opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+		}
+		exporter, err = otlptracehttp.New(ctx, opts...)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("create %s trace exporter: %w", cfg.ExporterName(), err)
+	}
+	if exporter != nil {
+		// OTEL_SERVICE_NAME and OTEL_RESOURCE_ATTRIBUTES override the
+		// service name.
+		res, err := resource.New(ctx,
+			resource.WithAttributes(attribute.String("service.name", serviceName)),
+			resource.WithFromEnv(),
+			resource.WithTelemetrySDK(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("tracing resource: %w", err)
+		}
+		t.provider = sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+		otel.SetTracerProvider(t.provider)
+	}
+
+	t.Interceptors, err = Interceptors(otel.GetTracerProvider())
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Shutdown flushes buffered spans and stops the exporter.
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+	return t.provider.Shutdown(ctx)
+}
+
+// Interceptors returns client interceptors recording spans with tp. Spans
+// of onboarding and identity verification workflows, their activities and
+// the client calls starting and signalling them carry the merchant ID.
+func Interceptors(tp trace.TracerProvider) ([]interceptor.ClientInterceptor, error) {
+	if tp == nil {
+		return nil, errors.New("tracing: nil tracer provider")
+	}
+	tracing, err := otelinterceptor.NewTracingInterceptor(otelinterceptor.TracerOptions{
+		Tracer: tp.Tracer("temporal-customer-onboarding"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("tracing interceptor: %w", err)
+	}
+	// The merchant interceptor runs inside the tracing one, where the span
+	// is already in the context.
+	return []interceptor.ClientInterceptor{tracing, &merchantInterceptor{}}, nil
+}
+
+// Transport wraps base, or http.DefaultTransport if nil, so outbound
+// requests carry the trace context and are recorded as client spans.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}
+
+// Handler wraps h so each request is recorded as a server span continuing
+// the caller's trace.
+func Handler(h http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(h, operation)
+}
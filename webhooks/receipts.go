@@ -0,0 +1,143 @@
+// Package webhooks receives callbacks from external providers and forwards
+// them into the relevant workflow as signals.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"go.temporal.io/api/serviceerror"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// Signaler is the subset of client.Client used to deliver receipts.
+type Signaler interface {
+	SignalWorkflow(ctx context.Context, workflowID string, runID string, signalName string, arg interface{}) error
+}
+
+// ReceiptHandler accepts delivery receipts from the notification provider
+// and signals each one into the merchant's OnboardingWorkflow.
+//
+// The body is either a single shared.DeliveryReceipt or a JSON array of them,
+// since most providers batch their event webhooks. Requests must carry the
+// provider's signature of the body in SignatureHeader; unsigned or wrongly
+// signed requests are rejected, and so is every request when Secret is empty.
+type ReceiptHandler struct {
+	Signaler Signaler
+	// Secret is the signing secret shared with the notification provider.
+	Secret []byte
+}
+
+// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the
+// request body, keyed with the shared signing secret.
+const SignatureHeader = "X-Signature"
+
+// maxReceiptBody bounds the size of a receipt batch.
+const maxReceiptBody = 1 << 20
+
+// NewReceiptHandler returns a ReceiptHandler that signals through s and
+// verifies requests with secret.
+func NewReceiptHandler(s Signaler, secret string) *ReceiptHandler {
+	return &ReceiptHandler{Signaler: s, Secret: []byte(secret)}
+}
+
+// Sign returns the SignatureHeader value for body under secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ServeHTTP implements http.Handler.
+func (h *ReceiptHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(rw, r.Body, maxReceiptBody))
+	if err != nil {
+		http.Error(rw, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !h.verify(r.Header.Get(SignatureHeader), body) {
+		http.Error(rw, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	receipts, err := decodeReceipts(body)
+	if err != nil {
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	for _, receipt := range receipts {
+		workflowID := shared.OnboardingWorkflowID(receipt.MerchantID)
+		err := h.Signaler.SignalWorkflow(r.Context(), workflowID, "", shared.SignalDeliveryReceipt, receipt)
+		var notFound *serviceerror.NotFound
+		switch {
+		case errors.As(err, &notFound):
+			// The onboarding already finished; the receipt is no longer actionable.
+			log.Printf("Dropping receipt for %s: workflow not running", workflowID)
+		case err != nil:
+			// Fail the batch so the provider redelivers it. Receipts already
+			// signalled are simply recorded twice.
+			http.Error(rw, fmt.Sprintf("failed to signal %s: %v", workflowID, err), http.StatusBadGateway)
+			return
+		}
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+// verify reports whether signature is the signature of body under the
+// handler's secret.
+func (h *ReceiptHandler) verify(signature string, body []byte) bool {
+	if len(h.Secret) == 0 || !strings.HasPrefix(signature, "sha256=") {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(h.Secret, body)))
+}
+
+func decodeReceipts(body []byte) ([]shared.DeliveryReceipt, error) {
+	var raw json.RawMessage
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	var receipts []shared.DeliveryReceipt
+	if len(raw) > 0 && raw[0] == '[' {
+		if err := json.Unmarshal(raw, &receipts); err != nil {
+			return nil, fmt.Errorf("invalid receipt batch: %w", err)
+		}
+	} else {
+		var receipt shared.DeliveryReceipt
+		if err := json.Unmarshal(raw, &receipt); err != nil {
+			return nil, fmt.Errorf("invalid receipt: %w", err)
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	for i, receipt := range receipts {
+		if receipt.MerchantID == "" {
+			return nil, fmt.Errorf("receipt %d: merchantId is required", i)
+		}
+		switch receipt.Status {
+		case shared.DeliveryDelivered, shared.DeliveryBounced, shared.DeliveryComplained:
+		default:
+			return nil, fmt.Errorf("receipt %d: unknown status %q", i, receipt.Status)
+		}
+	}
+	return receipts, nil
+}
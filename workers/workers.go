@@ -0,0 +1,288 @@
+// Package workers assembles onboarding worker processes. A process runs the
+// workflow worker, the activity worker or both, sharing one configuration,
+// Temporal client, metrics registry and set of health probes. The separate
+// workers/onboarding and workers/activity binaries and the combined
+// cmd/onboarding binary are built on it.
+package workers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	tlog "go.temporal.io/sdk/log"
+	"go.temporal.io/sdk/worker"
+
+	"temporal-customer-onboarding/activities"
+	"temporal-customer-onboarding/config"
+	"temporal-customer-onboarding/health"
+	"temporal-customer-onboarding/logging"
+	"temporal-customer-onboarding/metrics"
+	"temporal-customer-onboarding/payments"
+	"temporal-customer-onboarding/tracing"
+	"temporal-customer-onboarding/workflows"
+)
+
+// Role is a worker a process can run.
+type Role string
+
+const (
+	// RoleWorkflow runs OnboardingWorkflow and IdentityVerificationWorkflow
+	// on the workflow task queue.
+	RoleWorkflow Role = "workflow"
+	// RoleActivity runs the activities on the activity task queue.
+	RoleActivity Role = "activity"
+)
+
+// ParseRoles parses "workflow", "activity", "all", or a comma-separated
+// list of roles.
+func ParseRoles(s string) ([]Role, error) {
+	if s == "all" {
+		return []Role{RoleWorkflow, RoleActivity}, nil
+	}
+	var roles []Role
+	seen := make(map[Role]bool)
+	for _, name := range strings.Split(s, ",") {
+		role := Role(strings.TrimSpace(name))
+		switch role {
+		case RoleWorkflow, RoleActivity:
+		default:
+			return nil, fmt.Errorf("unknown role %q: want workflow, activity or all", name)
+		}
+		if !seen[role] {
+			seen[role] = true
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// Process is a worker process: the configuration, JSON logging, Prometheus
+// metrics, tracing and Temporal client its workers share.
+type Process struct {
+	Config  config.Config
+	Client  client.Client
+	Metrics *metrics.Prometheus
+	// Health serves the probes; Run marks the process ready once every
+	// worker is polling.
+	Health *health.Checker
+
+	tracer      *tracing.Tracing
+	workers     []worker.Worker
+	stopTimeout time.Duration
+	closers     []func()
+}
+
+// NewProcess loads the configuration, sets up logging, metrics and tracing
+// for serviceName, and connects to Temporal. Call Close when done.
+func NewProcess(serviceName string) (*Process, error) {
+	// Address, namespace, TLS, API key, payload encryption, tuning, logging
+	// and trace export come from the config file and environment; see the
+	// config package.
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration: %w", err)
+	}
+
+	// JSON logs on stderr. Workflow and activity records carry the workflow,
+	// run, activity, attempt and merchant ID; the standard log package
+	// writes through the same handler.
+	logger := logging.New(os.Stderr, cfg.Logging)
+	slog.SetDefault(logger)
+
+	p := &Process{Config: cfg, Metrics: metrics.NewPrometheus(), Health: health.NewChecker()}
+	p.closers = append(p.closers, func() { p.Metrics.Close() })
+
+	// Spans for workflows and activities join the trace of whoever started
+	// the onboarding; see the tracing package.
+	p.tracer, err = tracing.Setup(context.Background(), cfg.Tracing, serviceName)
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("set up tracing: %w", err)
+	}
+	p.closers = append(p.closers, func() { p.tracer.Shutdown(context.Background()) })
+
+	// SDK worker metrics and the onboarding business metrics go to the
+	// shared registry.
+	p.Client, err = cfg.Dial(func(opts *client.Options) {
+		opts.MetricsHandler = p.Metrics.Handler()
+		opts.Logger = tlog.NewStructuredLogger(logger)
+		opts.Interceptors = append(opts.Interceptors, p.tracer.Interceptors...)
+		opts.Interceptors = append(opts.Interceptors, logging.Interceptor())
+	})
+	if err != nil {
+		p.Close()
+		return nil, err
+	}
+	p.closers = append(p.closers, p.Client.Close)
+	return p, nil
+}
+
+// Serve serves /metrics on metricsAddr and the /livez and /readyz probes
+// on healthAddr in the background, on one listener when the addresses
+// match. An empty address disables the endpoint.
+func (p *Process) Serve(metricsAddr, healthAddr string) {
+	probeExtras := map[string]http.Handler{}
+	switch {
+	case metricsAddr != "" && metricsAddr == healthAddr:
+		probeExtras["/metrics"] = p.Metrics
+	case metricsAddr != "":
+		srv := p.Metrics.Serve(metricsAddr)
+		p.closers = append(p.closers, func() { srv.Close() })
+		log.Printf("Serving metrics on %s/metrics", metricsAddr)
+	}
+	if healthAddr != "" {
+		srv := p.Health.Serve(healthAddr, probeExtras)
+		p.closers = append(p.closers, func() { srv.Close() })
+		log.Printf("Serving health probes on %s/livez and /readyz", healthAddr)
+	}
+}
+
+// Add creates the worker for role on its configured task queue.
+func (p *Process) Add(role Role) error {
+	switch role {
+	case RoleWorkflow:
+		p.addWorkflowWorker()
+		return nil
+	case RoleActivity:
+		return p.addActivityWorker()
+	}
+	return fmt.Errorf("unknown role %q", role)
+}
+
+// WorkerOptions returns the configured tuning for role.
+func (p *Process) WorkerOptions(role Role) config.WorkerOptions {
+	if role == RoleActivity {
+		return p.Config.Workers.Activity
+	}
+	return p.Config.Workers.Workflow
+}
+
+func (p *Process) addWorker(taskQueue string, o config.WorkerOptions, opts worker.Options) worker.Worker {
+	w := worker.New(p.Client, taskQueue, opts)
+	p.workers = append(p.workers, w)
+	p.stopTimeout = max(p.stopTimeout, o.StopTimeout)
+	return w
+}
+
+func (p *Process) addWorkflowWorker() {
+	// Task queue and worker tuning (concurrency, pollers, sticky timeout)
+	// come from the workers.workflow section of the config.
+	o := p.Config.Workers.Workflow
+	w := p.addWorker(p.Config.TaskQueues.Workflow, o, o.Options())
+	w.RegisterWorkflow(workflows.OnboardingWorkflow)
+	w.RegisterWorkflow(workflows.IdentityVerificationWorkflow)
+	log.Printf("Starting onboarding workflow worker on task queue %s...", p.Config.TaskQueues.Workflow)
+}
+
+func (p *Process) addActivityWorker() error {
+	a, err := p.newActivities()
+	if err != nil {
+		return err
+	}
+	// Task queue and worker tuning come from the workers.activity section of
+	// the config. maxConcurrentActivities and taskQueueActivitiesPerSecond
+	// protect rate-limited downstream services such as the KYC supplier;
+	// stopTimeout gives in-flight activities time to finish during deploys.
+	o := p.Config.Workers.Activity
+	opts := o.Options()
+	opts.Interceptors = append(opts.Interceptors, p.Health.Interceptor())
+	w := p.addWorker(p.Config.TaskQueues.Activity, o, opts)
+	w.RegisterActivity(a)
+	log.Printf("Starting activity worker on task queue %s...", p.Config.TaskQueues.Activity)
+	return nil
+}
+
+// newActivities builds the activities with the dependencies configured in
+// the environment.
+func (p *Process) newActivities() (*activities.Activities, error) {
+	// Sent reminder keys are persisted locally so retries and resets never
+	// deliver the same reminder twice. Point this at durable storage in production.
+	storePath := os.Getenv("REMINDER_STORE_PATH")
+	if storePath == "" {
+		storePath = "data/sent-reminders.jsonl"
+	}
+	sentReminders, err := activities.OpenFileSentReminderStore(storePath)
+	if err != nil {
+		return nil, fmt.Errorf("open sent reminder store: %w", err)
+	}
+	p.closers = append(p.closers, func() { sentReminders.Close() })
+
+	// Internal ops alerts go to a chat incoming webhook when configured.
+	var ops *activities.OpsAlerter
+	if webhookURL := os.Getenv("OPS_WEBHOOK_URL"); webhookURL != "" {
+		uiURL := os.Getenv("TEMPORAL_UI_URL")
+		if uiURL == "" {
+			uiURL = "http://localhost:8233"
+		}
+		ops = activities.NewOpsAlerter(webhookURL, uiURL)
+		ops.HTTPClient.Transport = tracing.Transport(nil)
+
+		// Alerts held back for the rate-limited digest are kept on disk and
+		// posted on shutdown, so a restart or drain doesn't lose them.
+		queuePath := os.Getenv("OPS_ALERT_QUEUE_PATH")
+		if queuePath == "" {
+			queuePath = "data/ops-alerts.jsonl"
+		}
+		if err := ops.OpenQueue(queuePath); err != nil {
+			return nil, fmt.Errorf("open ops alert queue: %w", err)
+		}
+		p.closers = append(p.closers, func() { ops.Close() })
+	}
+
+	// Payment platform client. Without PAYMENTS_API_URL, payment activities only log.
+	var paymentsAPI activities.PaymentsAPI
+	if baseURL := os.Getenv("PAYMENTS_API_URL"); baseURL != "" {
+		paymentsClient, err := payments.NewClient(payments.Config{
+			BaseURL: baseURL,
+			Token:   os.Getenv("PAYMENTS_API_TOKEN"),
+			Timeout: 10 * time.Second,
+			// Payment platform calls join the activity's trace.
+			Transport: tracing.Transport(nil),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("create payments client: %w", err)
+		}
+		paymentsAPI = paymentsClient
+	}
+
+	// Status events go to the API server, which streams them to the portal.
+	// Without STATUS_EVENTS_URL, PublishStatusEvent only logs.
+	var statusEvents activities.StatusPublisher
+	if eventsURL := os.Getenv("STATUS_EVENTS_URL"); eventsURL != "" {
+		statusEvents = &activities.HTTPStatusPublisher{
+			URL:        eventsURL,
+			Token:      os.Getenv("STATUS_EVENTS_TOKEN"),
+			HTTPClient: &http.Client{Timeout: 5 * time.Second, Transport: tracing.Transport(nil)},
+		}
+	}
+
+	// In production, inject real dependencies here (e.g., API keys, DB connections).
+	return &activities.Activities{
+		SentReminders: sentReminders,
+		Payments:      paymentsAPI,
+		Ops:           ops,
+		StatusEvents:  statusEvents,
+	}, nil
+}
+
+// Run starts the workers and blocks until SIGINT or SIGTERM, then drains
+// them: they stop polling, the workflow worker finishes the tasks in hand
+// and in-flight activities get their worker's stopTimeout to finish.
+func (p *Process) Run() error {
+	return p.Health.Run(worker.InterruptCh(), p.stopTimeout, p.workers...)
+}
+
+// Close releases everything NewProcess, Serve and Add set up, in reverse
+// order.
+func (p *Process) Close() {
+	for i := len(p.closers) - 1; i >= 0; i-- {
+		p.closers[i]()
+	}
+}
@@ -0,0 +1,114 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// trackDeadline sets up tracking of the compliance deadline, and
+// startDeadline starts its timer. The deadline timer runs independently of
+// the reminder timers, so time spent sending reminders can never push the
+// deadline back.
+func (w *onboardingWorkflow) trackDeadline(ctx workflow.Context) {
+	deadlineCtx, cancel := workflow.WithCancel(ctx)
+	future, settable := workflow.NewFuture(deadlineCtx)
+
+	w.deadlinePassed = future
+	w.deadlineMovedCh = workflow.NewBufferedChannel(ctx, 1)
+	w.cancelDeadline = cancel
+	w.startDeadlineTimer = func() {
+		workflow.Go(deadlineCtx, func(ctx workflow.Context) {
+			w.runDeadline(ctx, settable)
+		})
+	}
+}
+
+// startDeadline starts the deadline timer unless it is already running.
+// Executions started before the reminder timeline only start it once their
+// reminders are done, or when early KYC moves the deadline forward.
+func (w *onboardingWorkflow) startDeadline() {
+	if w.startDeadlineTimer != nil {
+		w.startDeadlineTimer()
+		w.startDeadlineTimer = nil
+	}
+}
+
+// stopDeadline stops the deadline timer once the documents arrived.
+// Executions started before the reminder timeline never cancelled it; it
+// fires unobserved.
+func (w *onboardingWorkflow) stopDeadline() {
+	if w.timelineVersion != workflow.DefaultVersion {
+		w.cancelDeadline()
+	}
+}
+
+// runDeadline owns the deadline timer. It resolves passed when w.deadline is
+// reached, restarting the timer whenever moveDeadline changes the deadline,
+// and stops silently when cancelled because the documents arrived.
+func (w *onboardingWorkflow) runDeadline(ctx workflow.Context, passed workflow.Settable) {
+	for {
+		timerCtx, timerCancel := workflow.WithCancel(ctx)
+		timer := workflow.NewTimer(timerCtx, w.deadline.Sub(workflow.Now(ctx)))
+
+		moved := false
+		selector := workflow.NewSelector(ctx)
+		selector.AddFuture(timer, func(f workflow.Future) {})
+		selector.AddReceive(w.deadlineMovedCh, func(ch workflow.ReceiveChannel, more bool) {
+			ch.Receive(ctx, nil)
+			moved = true
+			timerCancel()
+		})
+		selector.Select(ctx)
+
+		if ctx.Err() != nil {
+			return // Documents arrived; the deadline no longer applies.
+		}
+		if !moved {
+			passed.Set(nil, nil)
+			return
+		}
+	}
+}
+
+// moveDeadline changes the compliance deadline while the merchant is still
+// expected to submit documents.
+func (w *onboardingWorkflow) moveDeadline(deadline time.Time) {
+	w.deadline = deadline
+	if w.startDeadlineTimer != nil {
+		return // The timer isn't running yet; it starts from w.deadline.
+	}
+	// One pending notification is enough: runDeadline re-reads w.deadline.
+	w.deadlineMovedCh.SendAsync(struct{}{})
+}
+
+// handleDeadlineExtensions runs for the lifetime of the workflow, pushing the
+// deadline back for every SignalExtendDeadline received while documents are
+// outstanding. Reminders and restrictions keep their original schedule.
+func (w *onboardingWorkflow) handleDeadlineExtensions(ctx workflow.Context) {
+	for {
+		var ext shared.DeadlineExtension
+		w.extendCh.Receive(ctx, &ext)
+
+		if w.repeatedRequest("extend", ext.RequestID) {
+			w.logger.Info("Ignoring repeated deadline extension", "requestId", ext.RequestID)
+			continue
+		}
+		if ext.Days <= 0 || w.documentID != "" || w.deadlinePassed.IsReady() {
+			w.logger.Info("Ignoring deadline extension",
+				"days", ext.Days,
+				"status", w.status,
+			)
+			continue
+		}
+
+		w.moveDeadline(w.deadline.Add(time.Duration(ext.Days) * 24 * time.Hour))
+		w.logger.Info("Deadline extended",
+			"days", ext.Days,
+			"reason", ext.Reason,
+			"deadline", w.deadline,
+		)
+	}
+}
@@ -12,18 +12,22 @@ import (
 
 // IdentityVerificationWorkflow is a child workflow that orchestrates KYC verification.
 // It validates a merchant's identity document with a 3rd party supplier,
-// then runs internal identity verification.
-func IdentityVerificationWorkflow(ctx workflow.Context, merchantID string, documentID string) (shared.VerificationResult, error) {
+// then runs internal identity verification. activityTaskQueue is where its
+// activities run; empty means shared.ActivityTaskQueue, as for children
+// started before it was passed.
+func IdentityVerificationWorkflow(ctx workflow.Context, merchantID string, documentID string, activityTaskQueue string) (shared.VerificationResult, error) {
+	if activityTaskQueue == "" {
+		activityTaskQueue = shared.ActivityTaskQueue
+	}
 	logger := workflow.GetLogger(ctx)
 	logger.Info("Identity verification workflow started",
-		"merchantId", merchantID,
 		"documentId", documentID,
 	)
 
 	// Activity options for ValidateWithSupplier.
 	// External API calls get more time and retries.
 	supplierOpts := workflow.ActivityOptions{
-		TaskQueue:           shared.ActivityTaskQueue,
+		TaskQueue:           activityTaskQueue,
 		StartToCloseTimeout: 30 * time.Second,
 		RetryPolicy: &temporal.RetryPolicy{
 			InitialInterval:        time.Second,
@@ -36,7 +40,7 @@ func IdentityVerificationWorkflow(ctx workflow.Context, merchantID string, docum
 	// Activity options for PerformInternalVerifications.
 	// Internal database checks — faster and more reliable than external APIs.
 	internalOpts := workflow.ActivityOptions{
-		TaskQueue:           shared.ActivityTaskQueue,
+		TaskQueue:           activityTaskQueue,
 		StartToCloseTimeout: 10 * time.Second,
 		RetryPolicy: &temporal.RetryPolicy{
 			MaximumAttempts: 2,
@@ -54,11 +58,12 @@ func IdentityVerificationWorkflow(ctx workflow.Context, merchantID string, docum
 	supplierCtx := workflow.WithActivityOptions(ctx, supplierOpts)
 	err := workflow.ExecuteActivity(supplierCtx, a.ValidateWithSupplier, doc).Get(ctx, &supplierResult)
 	if err != nil {
-		logger.Error("Supplier validation failed", "merchantId", merchantID, "error", err)
+		logger.Error("Supplier validation failed", "error", err)
 		return shared.VerificationResult{
 			Passed:         false,
 			VerificationID: fmt.Sprintf("KYC-FAIL-%s", merchantID),
 			Details:        fmt.Sprintf("Supplier validation failed: %v", err),
+			ReasonCodes:    []string{shared.ReasonSupplierRejected},
 		}, nil // Return result, not error — KYC rejection is a business outcome, not a workflow failure.
 	}
 	logger.Info("Supplier validation passed", "verificationId", supplierResult.VerificationID)
@@ -68,19 +73,33 @@ func IdentityVerificationWorkflow(ctx workflow.Context, merchantID string, docum
 	internalCtx := workflow.WithActivityOptions(ctx, internalOpts)
 	err = workflow.ExecuteActivity(internalCtx, a.PerformInternalVerifications, merchantID).Get(ctx, &internalResult)
 	if err != nil {
-		logger.Error("Internal verifications failed", "merchantId", merchantID, "error", err)
+		logger.Error("Internal verifications failed", "error", err)
 		return shared.VerificationResult{
 			Passed:         false,
 			VerificationID: fmt.Sprintf("KYC-FAIL-%s", merchantID),
 			Details:        fmt.Sprintf("Internal verifications failed: %v", err),
+			ReasonCodes:    []string{shared.ReasonInternalVerificationFailed},
 		}, nil
 	}
 	logger.Info("Internal verifications passed", "verificationId", internalResult.VerificationID)
 
-	// All checks passed.
-	return shared.VerificationResult{
+	// All checks passed. Conditions from either step carry over to the
+	// approval; where both set one, the stricter wins.
+	result := shared.VerificationResult{
 		Passed:         true,
 		VerificationID: fmt.Sprintf("KYC-%s", merchantID),
 		Details:        "All KYC checks passed (supplier + internal)",
-	}, nil
+	}
+	var conditions shared.MerchantConditions
+	for _, r := range []shared.VerificationResult{supplierResult, internalResult} {
+		if r.Conditions != nil {
+			conditions = conditions.Merge(*r.Conditions)
+		}
+	}
+	if conditions.Any() {
+		result.Conditions = &conditions
+		result.Details = "KYC checks passed with conditions (supplier + internal)"
+		logger.Info("Verification passed with conditions", "reason", conditions.Reason)
+	}
+	return result, nil
 }
@@ -0,0 +1,35 @@
+package workflows
+
+import (
+	"errors"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// metrics returns the workflow's metrics handler tagged with the merchant's
+// country. The handler drops everything recorded during replay, so each
+// metric is counted once per execution and needs no version marker.
+func (w *onboardingWorkflow) metrics(ctx workflow.Context) client.MetricsHandler {
+	return workflow.GetMetricsHandler(ctx).WithTags(map[string]string{"country": w.req.Merchant.Country})
+}
+
+// recordCompleted counts the finished onboarding by outcome.
+func (w *onboardingWorkflow) recordCompleted(ctx workflow.Context, result shared.OnboardingResult, err error) {
+	outcome := string(result.Outcome)
+	switch {
+	case w.status == shared.StatusCancelled || errors.Is(err, workflow.ErrCanceled):
+		outcome = shared.MetricOutcomeCancelled
+	case err != nil:
+		outcome = shared.MetricOutcomeFailed
+	}
+	w.metrics(ctx).WithTags(map[string]string{"outcome": outcome}).Counter(shared.MetricOnboardingCompleted).Inc(1)
+}
+
+// recordKYCAttempt counts a KYC attempt by result: passed, rejected or
+// error.
+func (w *onboardingWorkflow) recordKYCAttempt(ctx workflow.Context, result string) {
+	w.metrics(ctx).WithTags(map[string]string{"result": result}).Counter(shared.MetricKYCAttempts).Inc(1)
+}
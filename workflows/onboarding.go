@@ -2,12 +2,14 @@ package workflows
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"go.temporal.io/sdk/log"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 
+	"temporal-customer-onboarding/calendar"
 	"temporal-customer-onboarding/shared"
 )
 
@@ -15,38 +17,168 @@ import (
 // of the onboarding process.
 type onboardingWorkflow struct {
 	// Business state
-	status     shared.OnboardingStatus
-	documentID string
-	startTime  time.Time
+	status      shared.OnboardingStatus
+	conditions  *shared.MerchantConditions // Set when approved with conditions.
+	restriction shared.RestrictionLevel
+	documentID  string
+	startTime   time.Time // First payment; the timeline runs from here.
+	runStart    time.Time // When this workflow started.
+	submittedAt time.Time
+	deadline    time.Time
+
+	// Result history, reported in the OnboardingResult.
+	remindersSent        []string
+	restrictionsApplied  []shared.RestrictionLevel
+	restrictionsInFlight int  // Restriction activities not yet completed.
+	typedResult          bool // False for executions started before OnboardingResult.
+
+	// Deadline tracking (see deadline.go). deadlinePassed is resolved once
+	// the current deadline is reached and is shared by every phase.
+	deadlinePassed  workflow.Future
+	deadlineMovedCh workflow.Channel
+	cancelDeadline  workflow.CancelFunc
+	deadlineReached bool
+	// startDeadlineTimer starts the deadline timer; nil once it has.
+	startDeadlineTimer func()
+
+	// Indexed search attributes (see search_attributes.go), as last upserted.
+	searchAttributes   bool // False for executions started before search attributes.
+	kycAttempts        int
+	indexed            bool
+	indexedStatus      shared.OnboardingStatus
+	indexedDeadline    time.Time
+	indexedKYCAttempts int
+
+	// Status events (see status_events.go), queued for publishing.
+	publishStatus       bool // False for executions started before status events.
+	statusEvents        []shared.StatusEvent
+	statusSeq           int
+	statusEventsClosed  bool
+	statusWatchDone     bool
+	statusEventsFlushed bool
+
+	// Reminder delivery state
+	location        *time.Location
+	sendWindow      shared.SendWindow
+	reminderChannel string
+	sentReminders   map[string]shared.ReminderStep // reminderID → reminder sent
+	resends         int                            // Reminders resent on request.
+	deliveries      []shared.DeliveryReceipt
+	escalated       bool
+	escalateToOps   bool // False for executions started before escalations without an account manager went to ops.
+
+	// Payment event state
+	seenPayments      map[string]bool // event ID → seen, for the latest seenPaymentsWindow events
+	seenPaymentOrder  []string        // IDs in seenPayments, oldest first
+	paymentsSeen      int
+	processedVolume   map[string]int64 // currency → minor units
+	volumePolicy      shared.VolumePolicy
+	earlyKYCTriggered bool
 
 	// Workflow context
-	req      shared.OnboardingRequest
-	logger   log.Logger
-	actCtx   workflow.Context
-	signalCh workflow.ReceiveChannel
+	req        shared.OnboardingRequest
+	namespace  string
+	workflowID string
+	runID      string
+	firstRunID string
+	logger     log.Logger
+	actCtx     workflow.Context
+	signalCh   workflow.ReceiveChannel
+	receiptCh  workflow.ReceiveChannel
+	paymentCh  workflow.ReceiveChannel
+	extendCh   workflow.ReceiveChannel
+	resendCh   workflow.ReceiveChannel
+
+	// Versions of the guarded branches, pinned at start (see versions.go).
+	timelineVersion workflow.Version
+	expiryVersion   workflow.Version
+	kycVersion      workflow.Version
+
+	// handledRequests holds the request IDs of idempotent signals already
+	// applied, prefixed by signal kind.
+	handledRequests map[string]bool
 }
 
 // newOnboardingWorkflow initializes the workflow struct, registers the query
 // handler, and sets up the signal channel and activity options.
 func newOnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (*onboardingWorkflow, error) {
 	w := &onboardingWorkflow{
-		status:    shared.StatusPending,
-		startTime: workflow.Now(ctx),
-		req:       req,
-		logger:    workflow.GetLogger(ctx),
-		signalCh:  workflow.GetSignalChannel(ctx, shared.SignalDocumentSubmitted),
+		status:          shared.StatusPending,
+		restriction:     shared.RestrictionNone,
+		startTime:       workflow.Now(ctx),
+		reminderChannel: shared.ChannelEmail,
+		sentReminders:   make(map[string]shared.ReminderStep),
+		req:             req,
+		logger:          workflow.GetLogger(ctx),
+		signalCh:        workflow.GetSignalChannel(ctx, shared.SignalDocumentSubmitted),
+		receiptCh:       workflow.GetSignalChannel(ctx, shared.SignalDeliveryReceipt),
+		paymentCh:       workflow.GetSignalChannel(ctx, shared.SignalPaymentReceived),
+		extendCh:        workflow.GetSignalChannel(ctx, shared.SignalExtendDeadline),
+		resendCh:        workflow.GetSignalChannel(ctx, shared.SignalResendReminder),
+		handledRequests: make(map[string]bool),
+		seenPayments:    make(map[string]bool),
+		processedVolume: make(map[string]int64),
+		volumePolicy:    shared.DefaultVolumePolicy,
+	}
+
+	// Migrated merchants keep the timeline that started with their first
+	// payment in the legacy system.
+	w.runStart = w.startTime
+	if req.FirstPaymentAt != nil && req.FirstPaymentAt.Before(w.startTime) {
+		w.startTime = *req.FirstPaymentAt
+	}
+	w.deadline = w.startTime.Add(shared.DeadlineDay90)
+
+	// Reminders go out during the merchant's local business hours.
+	w.location = calendar.MerchantLocation(req.Merchant)
+	w.sendWindow = shared.DefaultSendWindow
+	if req.ReminderWindow != nil {
+		if req.ReminderWindow.Valid() {
+			w.sendWindow = *req.ReminderWindow
+		} else {
+			w.logger.Warn("Ignoring invalid reminder window, using default",
+				"window", *req.ReminderWindow,
+			)
+		}
+	}
+
+	if req.VolumePolicy != nil {
+		w.volumePolicy = *req.VolumePolicy
+	}
+
+	// The first run ID survives resets, so reminder idempotency keys derived
+	// from it stay stable when an operator resets the workflow.
+	info := workflow.GetInfo(ctx)
+	w.namespace = info.Namespace
+	w.workflowID = info.WorkflowExecution.ID
+	w.runID = info.WorkflowExecution.RunID
+	w.firstRunID = info.FirstRunID
+	if w.firstRunID == "" {
+		w.firstRunID = info.WorkflowExecution.RunID
 	}
 
 	// Register query handler so external clients can check status.
 	err := workflow.SetQueryHandler(ctx, shared.QueryOnboardingStatus, func() (shared.OnboardingStatusResponse, error) {
-		elapsed := workflow.Now(ctx).Sub(w.startTime)
-		daysRemaining := int((shared.DeadlineDay90 - elapsed).Hours() / 24)
+		daysRemaining := int(w.deadline.Sub(workflow.Now(ctx)).Hours() / 24)
 		if daysRemaining < 0 {
 			daysRemaining = 0
 		}
 		return shared.OnboardingStatusResponse{
 			Status:        w.status,
+			Deadline:      w.deadline,
 			DaysRemaining: daysRemaining,
+			Restriction:   w.restriction,
+			PaymentsSeen:  w.paymentsSeen,
+			Deliveries:    w.deliveries,
+			Escalated:     w.escalated,
+
+			ProcessedVolume:   w.processedVolume,
+			EarlyKYCTriggered: w.earlyKYCTriggered,
+
+			Conditions: w.conditions,
+
+			RunID:    w.runID,
+			Sequence: w.statusSeq,
 		}, nil
 	})
 	if err != nil {
@@ -55,7 +187,7 @@ func newOnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (
 
 	// Configure activity options.
 	actOpts := workflow.ActivityOptions{
-		TaskQueue:           shared.ActivityTaskQueue,
+		TaskQueue:           req.ActivityQueue(),
 		StartToCloseTimeout: 10 * time.Second,
 		RetryPolicy: &temporal.RetryPolicy{
 			MaximumAttempts: 3,
@@ -66,168 +198,555 @@ func newOnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (
 	return w, nil
 }
 
-// waitForDocumentWithReminders sends periodic reminders and listens for the
-// merchant's document submission signal. If the signal arrives during any
-// reminder wait, reminders are cancelled and we proceed immediately.
-func (w *onboardingWorkflow) waitForDocumentWithReminders(ctx workflow.Context) {
-	w.status = shared.StatusRemindersActive
+// reminderRequest builds the SendReminder input for the given reminder type
+// on the merchant's current channel, including its idempotency key.
+func (w *onboardingWorkflow) reminderRequest(reminderType, urgency string) shared.ReminderRequest {
+	keyType := reminderType
+	if w.reminderChannel != shared.ChannelEmail {
+		// A fallback send of the same reminder is a distinct delivery.
+		keyType = reminderType + ":" + w.reminderChannel
+	}
+	return shared.ReminderRequest{
+		MerchantID:     w.req.Merchant.MerchantID,
+		Email:          w.req.Merchant.Email,
+		Phone:          w.req.Merchant.Phone,
+		Channel:        w.reminderChannel,
+		ReminderType:   reminderType,
+		Urgency:        urgency,
+		IdempotencyKey: shared.ReminderIdempotencyKey(w.workflowID, w.firstRunID, keyType),
+	}
+}
+
+// sendReminder runs the SendReminder activity and remembers the reminder ID
+// so later delivery receipts can be matched back to the reminder type.
+func (w *onboardingWorkflow) sendReminder(ctx workflow.Context, reminderType, urgency string) (string, error) {
+	return w.deliverReminder(ctx, w.reminderRequest(reminderType, urgency))
+}
+
+// deliverReminder runs the SendReminder activity for req.
+func (w *onboardingWorkflow) deliverReminder(ctx workflow.Context, req shared.ReminderRequest) (string, error) {
+	var reminderID string
+	err := workflow.ExecuteActivity(w.actCtx, a.SendReminder, req).Get(ctx, &reminderID)
+	if err != nil {
+		return "", err
+	}
+	w.sentReminders[reminderID] = shared.ReminderStep{ReminderType: req.ReminderType, Urgency: req.Urgency}
+	w.remindersSent = append(w.remindersSent, req.ReminderType)
+	return reminderID, nil
+}
+
+// notifyOps alerts the internal support channel about a bad outcome. Failure
+// to alert never changes the onboarding result.
+func (w *onboardingWorkflow) notifyOps(ctx workflow.Context, outcome shared.OnboardingStatus, reason string) {
+	alert := shared.OpsAlert{
+		MerchantID:   w.req.Merchant.MerchantID,
+		MerchantName: w.req.Merchant.Name,
+		Country:      w.req.Merchant.Country,
+		Outcome:      outcome,
+		Reason:       reason,
+		Namespace:    w.namespace,
+		WorkflowID:   w.workflowID,
+		RunID:        w.runID,
+	}
+	if err := workflow.ExecuteActivity(w.actCtx, a.NotifyOps, alert).Get(ctx, nil); err != nil {
+		w.logger.Error("Failed to notify ops", "outcome", outcome, "error", err)
+	}
+}
+
+// handleDeliveryReceipts runs for the lifetime of the workflow, recording
+// delivery receipts reported by the notification provider. The first hard
+// bounce escalates to the account manager and switches reminders to the
+// fallback channel, so an unreachable merchant doesn't silently run into
+// the payment disable deadline.
+func (w *onboardingWorkflow) handleDeliveryReceipts(ctx workflow.Context) {
+	for {
+		var receipt shared.DeliveryReceipt
+		w.receiptCh.Receive(ctx, &receipt)
+		w.deliveries = append(w.deliveries, receipt)
+		w.logger.Info("Delivery receipt received",
+			"reminderId", receipt.ReminderID,
+			"status", receipt.Status,
+			"bounceType", receipt.BounceType,
+		)
 
-	reminders := []struct {
-		delay        time.Duration
-		reminderType string
-	}{
-		{shared.ReminderDay30, "day30"},
-		{shared.ReminderDay30, "day60"}, // Wait ANOTHER 30 days (Day 60 total)
+		if receipt.IsHardBounce() && !w.escalated {
+			w.escalate(ctx, receipt)
+		}
 	}
+}
 
-	for _, r := range reminders {
-		timerCtx, timerCancel := workflow.WithCancel(ctx)
-		timerFuture := workflow.NewTimer(timerCtx, r.delay)
+// escalate notifies the account manager about a hard bounce, or ops when the
+// merchant has none, and re-sends the bounced reminder over the fallback
+// channel when one is available.
+func (w *onboardingWorkflow) escalate(ctx workflow.Context, receipt shared.DeliveryReceipt) {
+	w.escalated = true
 
-		selector := workflow.NewSelector(ctx)
+	fallback := ""
+	if receipt.Channel != shared.ChannelSMS && w.req.Merchant.Phone != "" {
+		fallback = shared.ChannelSMS
+	}
 
-		selector.AddFuture(timerFuture, func(f workflow.Future) {
-			_ = f.Get(ctx, nil)
-		})
+	reason := fmt.Sprintf("Reminder %s hard-bounced on %s: %s", receipt.ReminderID, receipt.Channel, receipt.Reason)
+	if w.req.Merchant.AccountManagerEmail == "" && w.escalateToOps {
+		// Nobody owns the merchant; the support channel picks it up instead.
+		w.notifyOps(ctx, w.status, reason+" (no account manager assigned)")
+	} else {
+		escalation := shared.EscalationRequest{
+			MerchantID:          w.req.Merchant.MerchantID,
+			MerchantName:        w.req.Merchant.Name,
+			AccountManagerEmail: w.req.Merchant.AccountManagerEmail,
+			Reason:              reason,
+			FallbackChannel:     fallback,
+		}
+		if err := workflow.ExecuteActivity(w.actCtx, a.EscalateToAccountManager, escalation).Get(ctx, nil); err != nil {
+			w.logger.Error("Failed to escalate to account manager", "error", err)
+		}
+	}
+
+	if fallback == "" {
+		return
+	}
+	w.reminderChannel = fallback
 
-		selector.AddReceive(w.signalCh, func(ch workflow.ReceiveChannel, more bool) {
-			ch.Receive(ctx, &w.documentID)
-			w.logger.Info("Onboarding completion signal received during reminder phase",
-				"merchantId", w.req.Merchant.MerchantID,
-				"documentId", w.documentID,
+	// Re-send the bounced reminder unless the merchant has already moved on.
+	bounced, ok := w.sentReminders[receipt.ReminderID]
+	if !ok || w.status != shared.StatusRemindersActive {
+		return
+	}
+	if _, err := w.sendReminder(ctx, bounced.ReminderType, bounced.Urgency); err != nil {
+		w.logger.Error("Failed to send fallback reminder", "channel", fallback, "error", err)
+	}
+}
+
+// receiveDocument reads the document submission signal and stops the
+// deadline timer.
+func (w *onboardingWorkflow) receiveDocument(ctx workflow.Context, ch workflow.ReceiveChannel, phase string) {
+	ch.Receive(ctx, &w.documentID)
+	w.submittedAt = workflow.Now(ctx)
+	w.metrics(ctx).Timer(shared.MetricDocumentSubmissionLatency).Record(w.submittedAt.Sub(w.startTime))
+	w.logger.Info("Onboarding completion signal received during "+phase+" phase",
+		"documentId", w.documentID,
+	)
+	w.stopDeadline()
+}
+
+// reminderSendTime moves a reminder's due time into the merchant's local
+// send window on the next business day. The deadline itself is never moved:
+// if deferring would push the reminder past the deadline, it goes out in the
+// last send slot before the deadline instead, or at the original due time if
+// there is none.
+func (w *onboardingWorkflow) reminderSendTime(due time.Time) time.Time {
+	country := w.req.Merchant.Country
+	sendAt := calendar.NextSendTime(due, w.location, w.sendWindow, country)
+	if sendAt.Before(w.deadline) {
+		return sendAt
+	}
+	before := w.deadline.Add(-time.Second)
+	last, ok := calendar.PreviousSendTime(before, w.location, w.sendWindow, country)
+	if !ok || last.Before(w.runStart) {
+		return due
+	}
+	if w.timelineVersion < 3 && !last.Equal(before) {
+		// Executions pinned to earlier versions don't record send times and
+		// sent in the opening of the last window rather than its last slot.
+		y, m, d := last.Date()
+		return time.Date(y, m, d, w.sendWindow.StartHour, 0, 0, 0, w.location)
+	}
+	return last
+}
+
+// recordedSendTime returns reminderSendTime(due). It depends on the holiday
+// calendar and tz data compiled into the worker, so from timeline version 3
+// it is recorded in a side effect: replay on a worker with newer data then
+// can't move reminders, or reorder them against restrictions, in running
+// executions.
+func (w *onboardingWorkflow) recordedSendTime(ctx workflow.Context, due time.Time) time.Time {
+	if w.timelineVersion < 3 {
+		return w.reminderSendTime(due)
+	}
+	var sendAt time.Time
+	encoded := workflow.SideEffect(ctx, func(workflow.Context) interface{} {
+		return w.reminderSendTime(due)
+	})
+	if err := encoded.Get(&sendAt); err != nil {
+		w.logger.Error("Failed to decode recorded reminder send time", "error", err)
+		return due
+	}
+	return sendAt
+}
+
+// warnMissingHolidays logs when the holiday calendar has no data for part of
+// the merchant's onboarding period, so reminders may go out on a holiday.
+func (w *onboardingWorkflow) warnMissingHolidays() {
+	country := w.req.Merchant.Country
+	for _, t := range []time.Time{w.startTime, w.deadline} {
+		if !calendar.CoversHolidays(country, t.In(w.location)) {
+			w.logger.Warn("No public holiday data for the merchant's country; reminders only skip weekends",
+				"country", country,
+				"year", t.In(w.location).Year(),
 			)
-			timerCancel()
-		})
+			return
+		}
+	}
+}
+
+// timelineStep is a reminder or a restriction that falls due while the
+// merchant's documents are outstanding.
+type timelineStep struct {
+	at          time.Time
+	reminder    *shared.ReminderStep
+	restriction *shared.RestrictionStep
+}
 
-		selector.Select(ctx)
+// timeline merges the reminder schedule (moved into the merchant's send
+// window) with the restriction schedule for the merchant's risk tier, in
+// the order they fall due. Restrictions apply at their exact offset.
+func (w *onboardingWorkflow) timeline(ctx workflow.Context) []timelineStep {
+	w.warnMissingHolidays()
+	var steps []timelineStep
+	for i := range shared.ReminderSchedule {
+		r := &shared.ReminderSchedule[i]
+		steps = append(steps, timelineStep{at: w.recordedSendTime(ctx, w.startTime.Add(r.At)), reminder: r})
+	}
+	restrictions := shared.RestrictionSchedule(w.req.Merchant.RiskTier)
+	for i := range restrictions {
+		r := &restrictions[i]
+		steps = append(steps, timelineStep{at: w.startTime.Add(r.At), restriction: r})
+	}
+	sort.SliceStable(steps, func(i, j int) bool { return steps[i].at.Before(steps[j].at) })
+	return steps
+}
 
-		if w.documentID != "" {
-			break
+// waitForDocumentWithReminders works through the reminder and restriction
+// timeline while listening for the merchant's document submission signal.
+// If the signal arrives during any wait, the remaining steps are cancelled
+// and we proceed immediately. If the deadline fires first, the remaining
+// steps are skipped.
+func (w *onboardingWorkflow) waitForDocumentWithReminders(ctx workflow.Context) {
+	if w.documentID != "" {
+		return // Submitted before the workflow started.
+	}
+	w.status = shared.StatusRemindersActive
+
+	if w.timelineVersion == workflow.DefaultVersion {
+		w.waitWithLegacyReminders(ctx)
+		return
+	}
+
+	for _, step := range w.timeline(ctx) {
+		// Reminders that fell due before a migrated merchant's workflow
+		// started were the legacy system's to send.
+		if step.reminder != nil && step.at.Before(w.runStart) {
+			continue
+		}
+
+		// Offsets are absolute, so any latency from the previous step is
+		// absorbed rather than accumulated. Steps already due (same instant
+		// as the previous step, or the worker was down) run immediately.
+		if delay := step.at.Sub(workflow.Now(ctx)); delay > 0 && !w.waitForStep(ctx, delay) {
+			return
 		}
 
-		// Timer fired normally — send the reminder.
-		reminderReq := shared.ReminderRequest{
-			MerchantID:   w.req.Merchant.MerchantID,
-			Email:        w.req.Merchant.Email,
-			ReminderType: r.reminderType,
+		// Step is due — run it in the background so a slow or
+		// retrying activity never delays the deadline.
+		if step.restriction != nil {
+			r := *step.restriction
+			workflow.Go(ctx, func(ctx workflow.Context) {
+				w.applyRestriction(ctx, r)
+			})
+			continue
+		}
+		r := *step.reminder
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			reminderID, err := w.sendReminder(ctx, r.ReminderType, r.Urgency)
+			if err != nil {
+				w.logger.Error("Failed to send reminder", "reminderType", r.ReminderType, "error", err)
+				// Continue — a failed reminder shouldn't block the onboarding process.
+				return
+			}
+			w.logger.Info("Reminder sent", "reminderType", r.ReminderType, "urgency", r.Urgency, "reminderID", reminderID)
+		})
+	}
+}
+
+// waitWithLegacyReminders is the reminder phase of executions started before
+// the reminder timeline: each reminder in LegacyReminderSchedule is sent
+// when its timer fires, and the next timer starts only after it was sent.
+func (w *onboardingWorkflow) waitWithLegacyReminders(ctx workflow.Context) {
+	var elapsed time.Duration
+	for _, r := range shared.LegacyReminderSchedule {
+		if !w.waitForStep(ctx, r.At-elapsed) {
+			return
 		}
-		var reminderID string
-		err := workflow.ExecuteActivity(w.actCtx, a.SendReminder, reminderReq).Get(ctx, &reminderID)
+		elapsed = r.At
+
+		reminderID, err := w.sendReminder(ctx, r.ReminderType, r.Urgency)
 		if err != nil {
-			w.logger.Error("Failed to send reminder", "error", err)
+			w.logger.Error("Failed to send reminder", "reminderType", r.ReminderType, "error", err)
 			// Continue — a failed reminder shouldn't block the onboarding process.
+			continue
+		}
+		w.logger.Info("Reminder sent", "reminderType", r.ReminderType, "urgency", r.Urgency, "reminderID", reminderID)
+	}
+}
+
+// waitForStep waits until the next timeline step is due. It returns false if
+// the document arrived or the deadline passed first.
+func (w *onboardingWorkflow) waitForStep(ctx workflow.Context, delay time.Duration) bool {
+	timerCtx, timerCancel := workflow.WithCancel(ctx)
+	timerFuture := workflow.NewTimer(timerCtx, delay)
+
+	stepDue := false
+	selector := workflow.NewSelector(ctx)
+
+	selector.AddFuture(timerFuture, func(f workflow.Future) {
+		stepDue = f.Get(ctx, nil) == nil
+	})
+
+	selector.AddFuture(w.deadlinePassed, func(f workflow.Future) {
+		w.deadlineReached = true
+		timerCancel()
+	})
+
+	selector.AddReceive(w.signalCh, func(ch workflow.ReceiveChannel, more bool) {
+		w.receiveDocument(ctx, ch, "reminder")
+		timerCancel()
+	})
+
+	selector.Select(ctx)
+	return stepDue
+}
+
+// applyRestriction escalates the merchant's payment restrictions. A failed
+// restriction is logged and the onboarding continues; the day-90 disable
+// is the backstop.
+func (w *onboardingWorkflow) applyRestriction(ctx workflow.Context, r shared.RestrictionStep) {
+	w.logger.Info("Applying payment restriction",
+		"restriction", r.Level,
+	)
+	w.restrictionsInFlight++
+	defer func() { w.restrictionsInFlight-- }()
+
+	var err error
+	switch r.Level {
+	case shared.RestrictionPayoutHold:
+		err = workflow.ExecuteActivity(w.actCtx, a.HoldPayouts, w.req.Merchant.MerchantID).Get(ctx, nil)
+	case shared.RestrictionVolumeCap:
+		capReq := shared.VolumeCapRequest{
+			MerchantID:     w.req.Merchant.MerchantID,
+			DailyVolumeCap: r.DailyVolumeCap,
 		}
-		w.logger.Info("Reminder sent", "reminderType", r.reminderType, "reminderID", reminderID)
+		err = workflow.ExecuteActivity(w.actCtx, a.CapTransactionVolume, capReq).Get(ctx, nil)
+	default:
+		err = fmt.Errorf("unsupported restriction level %q", r.Level)
+	}
+	if err != nil {
+		w.logger.Error("Failed to apply payment restriction", "restriction", r.Level, "error", err)
+		return
+	}
+	w.restrictionsApplied = append(w.restrictionsApplied, r.Level)
+
+	// Only escalate: a payout hold finishing after the volume cap, or any
+	// restriction finishing after payments were disabled, must not weaken
+	// that state. Approval waits for restrictions in flight before lifting
+	// them (see liftRestrictions).
+	if w.restriction == shared.RestrictionNone || w.restriction == shared.RestrictionPayoutHold {
+		w.restriction = r.Level
+	}
+}
+
+// liftRestrictions removes any restrictions applied while documents were
+// outstanding. It first waits for restrictions still being applied, so none
+// can land after the lift and leave an approved merchant restricted.
+func (w *onboardingWorkflow) liftRestrictions(ctx workflow.Context) {
+	if w.kycVersion >= 2 {
+		_ = workflow.Await(ctx, func() bool { return w.restrictionsInFlight == 0 })
+	}
+	if w.restriction == shared.RestrictionNone {
+		return
+	}
+	err := workflow.ExecuteActivity(w.actCtx, a.LiftRestrictions, w.req.Merchant.MerchantID).Get(ctx, nil)
+	if err != nil {
+		w.logger.Error("Failed to lift payment restrictions", "restriction", w.restriction, "error", err)
+		return
 	}
+	w.logger.Info("Payment restrictions lifted")
+	w.restriction = shared.RestrictionNone
 }
 
 // waitForDeadline waits for the remaining time until the 90-day deadline
 // for the merchant to submit their document. If the signal arrives before
 // the deadline, the workflow proceeds. Otherwise, the document remains empty.
 func (w *onboardingWorkflow) waitForDeadline(ctx workflow.Context) {
-	if w.documentID != "" {
-		return // Already received during reminder phase.
+	if w.documentID != "" || w.deadlineReached || ctx.Err() != nil {
+		return // Already resolved during reminder phase.
 	}
-
-	remainingTime := shared.DeadlineDay90 - shared.ReminderDay60
+	w.startDeadline()
 
 	w.logger.Info("Waiting for onboarding completion before deadline",
-		"merchantId", w.req.Merchant.MerchantID,
-		"remainingTime", remainingTime,
+		"remainingTime", w.deadline.Sub(workflow.Now(ctx)),
 	)
 
 	// Wait for the deadline or the signal.
 	selector := workflow.NewSelector(ctx)
 
 	// Case 1: Deadline expires.
-	timerFuture := workflow.NewTimer(ctx, remainingTime)
-	selector.AddFuture(timerFuture, func(f workflow.Future) {
-		// Do nothing — simply let the selector return.
+	selector.AddFuture(w.deadlinePassed, func(f workflow.Future) {
 		// documentID remains empty.
+		w.deadlineReached = true
 	})
 
 	// Case 2: Signal arrives.
 	selector.AddReceive(w.signalCh, func(ch workflow.ReceiveChannel, more bool) {
-		ch.Receive(ctx, &w.documentID)
-		w.logger.Info("Signal received during deadline phase",
-			"merchantId", w.req.Merchant.MerchantID,
-			"documentId", w.documentID,
-		)
+		w.receiveDocument(ctx, ch, "deadline")
 	})
 
+	// Case 3: An operator cancelled the onboarding.
+	selector.AddReceive(ctx.Done(), func(ch workflow.ReceiveChannel, more bool) {})
+
 	selector.Select(ctx)
 }
 
+// result builds the workflow result for outcome from the workflow state.
+func (w *onboardingWorkflow) result(ctx workflow.Context, outcome shared.Outcome) shared.OnboardingResult {
+	r := shared.OnboardingResult{
+		Outcome:             outcome,
+		MerchantID:          w.req.Merchant.MerchantID,
+		StartedAt:           w.startTime,
+		DecidedAt:           workflow.Now(ctx),
+		RemindersSent:       w.remindersSent,
+		RestrictionsApplied: w.restrictionsApplied,
+		ResultCode:          shared.LegacyResultCode(w.req.Merchant.MerchantID, outcome),
+		Legacy:              !w.typedResult,
+	}
+	if !w.submittedAt.IsZero() {
+		submittedAt := w.submittedAt
+		r.DocumentSubmittedAt = &submittedAt
+	}
+	return r
+}
+
 // disablePayments handles the case where the merchant missed the 90-day deadline.
 // It disables payment processing and returns a failure result.
-func (w *onboardingWorkflow) disablePayments(ctx workflow.Context) (string, error) {
-	w.logger.Info("Onboarding deadline expired, disabling payments",
-		"merchantId", w.req.Merchant.MerchantID,
-	)
+func (w *onboardingWorkflow) disablePayments(ctx workflow.Context) (shared.OnboardingResult, error) {
+	w.logger.Info("Onboarding deadline expired, disabling payments")
 	w.status = shared.StatusPaymentsDisabled
 
 	err := workflow.ExecuteActivity(w.actCtx, a.DisablePayments, w.req.Merchant.MerchantID).Get(ctx, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to disable payments: %w", err)
+		return shared.OnboardingResult{}, fmt.Errorf("failed to disable payments: %w", err)
 	}
+	w.restriction = shared.RestrictionDisabled
+	w.restrictionsApplied = append(w.restrictionsApplied, shared.RestrictionDisabled)
 
-	return fmt.Sprintf("ONBOARD-%s-PAYMENTS-DISABLED", w.req.Merchant.MerchantID), nil
+	// Executions started before ops alerts existed don't send them.
+	if w.expiryVersion >= 1 {
+		w.notifyOps(ctx, shared.StatusPaymentsDisabled, "KYC documents not submitted before the 90-day deadline")
+	}
+
+	result := w.result(ctx, shared.OutcomePaymentsDisabled)
+	result.ReasonCodes = []string{shared.ReasonDocumentsNotSubmitted}
+	if w.earlyKYCTriggered {
+		result.ReasonCodes = append(result.ReasonCodes, shared.ReasonVolumeThresholdExceeded)
+	}
+	return result, nil
 }
 
 // runKYC launches the identity verification child workflow and handles
 // the result (approved or rejected).
-func (w *onboardingWorkflow) runKYC(ctx workflow.Context) (string, error) {
-	w.logger.Info("Merchant completed onboarding, starting KYC verification",
-		"merchantId", w.req.Merchant.MerchantID,
-	)
+func (w *onboardingWorkflow) runKYC(ctx workflow.Context) (shared.OnboardingResult, error) {
+	w.logger.Info("Merchant completed onboarding, starting KYC verification")
 	w.status = shared.StatusKYCInProgress
+	w.kycAttempts++
 
 	childOpts := workflow.ChildWorkflowOptions{
-		WorkflowID: fmt.Sprintf("kyc-verify-%s", w.req.Merchant.MerchantID),
-		TaskQueue:  shared.OnboardingWorkflowTaskQueue,
+		WorkflowID: shared.KYCWorkflowID(w.req.Merchant.MerchantID),
+		TaskQueue:  workflow.GetInfo(ctx).TaskQueueName,
 	}
 	childCtx := workflow.WithChildOptions(ctx, childOpts)
 
 	var kycResult shared.VerificationResult
-	err := workflow.ExecuteChildWorkflow(childCtx, IdentityVerificationWorkflow, w.req.Merchant.MerchantID, w.documentID).Get(ctx, &kycResult)
+	err := workflow.ExecuteChildWorkflow(childCtx, IdentityVerificationWorkflow, w.req.Merchant.MerchantID, w.documentID, w.req.ActivityTaskQueue).Get(ctx, &kycResult)
 	if err != nil {
-		return "", fmt.Errorf("KYC child workflow failed: %w", err)
+		w.recordKYCAttempt(ctx, "error")
+		return shared.OnboardingResult{}, fmt.Errorf("KYC child workflow failed: %w", err)
 	}
 
 	if !kycResult.Passed {
+		w.recordKYCAttempt(ctx, "rejected")
 		w.status = shared.StatusRejected
 		w.logger.Info("KYC verification failed",
-			"merchantId", w.req.Merchant.MerchantID,
 			"details", kycResult.Details,
 		)
 
 		// Notify merchant of rejection.
-		reminderReq := shared.ReminderRequest{
-			MerchantID:   w.req.Merchant.MerchantID,
-			Email:        w.req.Merchant.Email,
-			ReminderType: "kycRejection",
+		_, _ = w.sendReminder(ctx, "kycRejection", shared.UrgencyStandard)
+		if w.kycVersion >= 1 {
+			w.notifyOps(ctx, shared.StatusRejected, kycResult.Details)
 		}
-		_ = workflow.ExecuteActivity(w.actCtx, a.SendReminder, reminderReq).Get(ctx, nil)
 
-		return fmt.Sprintf("ONBOARD-%s-KYC-REJECTED", w.req.Merchant.MerchantID), nil
+		result := w.result(ctx, shared.OutcomeKYCRejected)
+		result.VerificationID = kycResult.VerificationID
+		result.ReasonCodes = kycResult.ReasonCodes
+		return result, nil
+	}
+
+	w.recordKYCAttempt(ctx, "passed")
+	if kycResult.Conditions != nil && kycResult.Conditions.Any() {
+		return w.approveWithConditions(ctx, kycResult)
 	}
 
 	// Success — all checks passed.
 	w.status = shared.StatusApproved
+	w.liftRestrictions(ctx)
 	w.logger.Info("Onboarding completed successfully",
-		"merchantId", w.req.Merchant.MerchantID,
 		"kycVerificationId", kycResult.VerificationID,
 	)
 
 	// Notify merchant of approval.
-	reminderReq := shared.ReminderRequest{
-		MerchantID:   w.req.Merchant.MerchantID,
-		Email:        w.req.Merchant.Email,
-		ReminderType: "onboardingApproved",
+	_, _ = w.sendReminder(ctx, "onboardingApproved", shared.UrgencyStandard)
+
+	result := w.result(ctx, shared.OutcomeApproved)
+	result.VerificationID = kycResult.VerificationID
+	return result, nil
+}
+
+// approveWithConditions completes onboarding for a merchant that compliance
+// approved only with processing conditions. The conditions are put in force
+// on the payments platform before onboarding restrictions are lifted.
+//
+// Unlike the other activities after KYC, a failure to apply the conditions
+// fails the workflow: the approval is only valid with the conditions in
+// force, so the merchant must not be reported as approved, or have its
+// restrictions lifted, without them. Ops are alerted to apply them by hand.
+func (w *onboardingWorkflow) approveWithConditions(ctx workflow.Context, kycResult shared.VerificationResult) (shared.OnboardingResult, error) {
+	// Executions started earlier lift the restrictions first.
+	if w.kycVersion < 3 {
+		w.liftRestrictions(ctx)
+	}
+
+	conditions := *kycResult.Conditions
+	req := shared.MerchantConditionsRequest{MerchantID: w.req.Merchant.MerchantID, Conditions: conditions}
+	err := workflow.ExecuteActivity(w.actCtx, a.ApplyMerchantConditions, req).Get(ctx, nil)
+	if err != nil {
+		w.notifyOps(ctx, w.status, fmt.Sprintf("approved with conditions, but they could not be applied: %v", err))
+		return shared.OnboardingResult{}, fmt.Errorf("failed to apply approval conditions: %w", err)
+	}
+	if w.kycVersion >= 3 {
+		w.liftRestrictions(ctx)
 	}
-	_ = workflow.ExecuteActivity(w.actCtx, a.SendReminder, reminderReq).Get(ctx, nil)
+	w.status = shared.StatusApprovedWithConditions
+	w.conditions = &conditions
+	w.logger.Info("Onboarding completed with conditions",
+		"reason", conditions.Reason,
+	)
+
+	_, _ = w.sendReminder(ctx, "onboardingApprovedWithConditions", shared.UrgencyStandard)
 
-	return fmt.Sprintf("ONBOARD-%s-APPROVED", w.req.Merchant.MerchantID), nil
+	result := w.result(ctx, shared.OutcomeApprovedWithConditions)
+	result.VerificationID = kycResult.VerificationID
+	result.Conditions = &conditions
+	return result, nil
 }
 
 // OnboardingWorkflow models Mollie's merchant onboarding compliance process.
@@ -240,23 +759,82 @@ func (w *onboardingWorkflow) runKYC(ctx workflow.Context) (string, error) {
 //	Day 0  → Workflow starts (first payment received)
 //	Day 30 → Send reminder
 //	Day 60 → Send reminder
+//	Day 75 → Hold payouts (standard risk tier)
+//	Day 83, 87, 89 → Send final warnings
+//	Day 85 → Cap transaction volume (standard risk tier)
 //	Day 90 → Deadline: if not completed, disable payments
 //
 // Temporal features demonstrated:
 //   - Durable timers (workflow.Sleep for reminder schedule)
-//   - Signals (SignalDocumentSubmitted)
+//   - Signals (SignalDocumentSubmitted, SignalDeliveryReceipt, SignalPaymentReceived)
+//   - SignalWithStart (first payment event starts the workflow)
 //   - Queries (GetOnboardingStatus)
 //   - Child workflows (Identity Verification)
 //   - Retry policies with non-retryable error types
-func OnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (string, error) {
+func OnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (shared.OnboardingResult, error) {
 	w, err := newOnboardingWorkflow(ctx, req)
 	if err != nil {
-		return "", err
+		return shared.OnboardingResult{}, err
 	}
 
-	w.logger.Info("Onboarding workflow started",
-		"merchantId", req.Merchant.MerchantID,
-	)
+	w.pinVersions(ctx)
+
+	w.logger.Info("Onboarding workflow started")
+	w.metrics(ctx).Counter(shared.MetricOnboardingStarted).Inc(1)
+
+	if w.searchAttributes {
+		workflow.Go(ctx, w.indexSearchAttributes)
+	}
+	if w.publishStatus {
+		w.startStatusEvents(ctx)
+	}
+
+	result, err := w.run(ctx)
+	w.recordCompleted(ctx, result, err)
+
+	if w.searchAttributes {
+		disconnectedCtx, _ := workflow.NewDisconnectedContext(ctx)
+		w.upsertSearchAttributes(disconnectedCtx)
+	}
+	if w.publishStatus {
+		w.flushStatusEvents(ctx)
+	}
+	return result, err
+}
+
+// run works through the onboarding phases and returns the outcome.
+func (w *onboardingWorkflow) run(ctx workflow.Context) (shared.OnboardingResult, error) {
+	req := w.req
+
+	// Requests that bypassed validation get the default volume policy.
+	// Executions started earlier keep the policy they started with.
+	if req.VolumePolicy != nil && w.timelineVersion >= 2 {
+		if err := req.VolumePolicy.Validate(); err != nil {
+			w.logger.Warn("Ignoring invalid volume policy, using default", "error", err)
+			w.volumePolicy = shared.DefaultVolumePolicy
+		}
+	}
+
+	// The deadline runs from workflow start regardless of reminders.
+	w.trackDeadline(ctx)
+
+	// Delivery receipts and payment events can arrive at any point, so they
+	// are handled in the background.
+	workflow.Go(ctx, w.handleDeliveryReceipts)
+	workflow.Go(ctx, w.handlePaymentEvents)
+
+	if w.timelineVersion != workflow.DefaultVersion {
+		w.startDeadline()
+	}
+	workflow.Go(ctx, w.handleDeadlineExtensions)
+	workflow.Go(ctx, w.handleReminderResends)
+
+	// Documents submitted before the workflow started go straight to KYC.
+	if req.DocumentID != "" {
+		w.documentID = req.DocumentID
+		w.submittedAt = w.runStart
+		w.stopDeadline()
+	}
 
 	// Phase 1: Send reminders while waiting for document submission.
 	w.waitForDocumentWithReminders(ctx)
@@ -264,6 +842,14 @@ func OnboardingWorkflow(ctx workflow.Context, req shared.OnboardingRequest) (str
 	// Phase 2: Wait for the 90-day deadline if document not yet received.
 	w.waitForDeadline(ctx)
 
+	// An operator cancelled the onboarding: stop without disabling payments
+	// or running KYC. Restrictions already applied stay in place.
+	if ctx.Err() != nil {
+		w.status = shared.StatusCancelled
+		w.logger.Info("Onboarding cancelled")
+		return shared.OnboardingResult{}, ctx.Err()
+	}
+
 	// Phase 3: Outcome — either disable payments or run KYC.
 	if w.documentID == "" {
 		return w.disablePayments(ctx)
@@ -0,0 +1,125 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// handlePaymentEvents runs for the lifetime of the workflow, recording the
+// payment events that the payment-event consumer signals in. The first event
+// usually arrives with the SignalWithStart that started this workflow.
+//
+// Processed volume is accumulated per currency; crossing the volume policy's
+// threshold while documents are still outstanding triggers early KYC.
+func (w *onboardingWorkflow) handlePaymentEvents(ctx workflow.Context) {
+	for {
+		var event shared.PaymentEvent
+		w.paymentCh.Receive(ctx, &event)
+
+		// Sources deliver at least once; the event ID drops redeliveries.
+		if event.EventID != "" && w.seenPayment(event.EventID) {
+			continue
+		}
+		w.paymentsSeen++
+		w.processedVolume[event.Currency] += event.Amount
+
+		w.logger.Info("Payment event received",
+			"eventId", event.EventID,
+			"amount", event.Amount,
+			"currency", event.Currency,
+		)
+
+		threshold, ok := w.volumePolicy.Thresholds[event.Currency]
+		if ok && !w.earlyKYCTriggered && w.processedVolume[event.Currency] >= threshold {
+			w.triggerEarlyKYC(ctx, event.Currency)
+		}
+	}
+}
+
+// seenPaymentsWindow is how many of the latest payment event IDs are kept
+// to drop redeliveries. Sources redeliver an event soon after the original,
+// so older IDs are forgotten rather than kept for the workflow's lifetime.
+const seenPaymentsWindow = 1000
+
+// seenPayment reports whether eventID was among the last seenPaymentsWindow
+// events, and records it otherwise.
+func (w *onboardingWorkflow) seenPayment(eventID string) bool {
+	if w.seenPayments[eventID] {
+		return true
+	}
+	w.seenPayments[eventID] = true
+	w.seenPaymentOrder = append(w.seenPaymentOrder, eventID)
+	if len(w.seenPaymentOrder) > seenPaymentsWindow {
+		delete(w.seenPayments, w.seenPaymentOrder[0])
+		w.seenPaymentOrder = w.seenPaymentOrder[1:]
+	}
+	return false
+}
+
+// triggerEarlyKYC shortens the deadline, sends an urgent reminder and,
+// if the policy says so, caps payments until documents arrive. It does
+// nothing once documents have been submitted.
+func (w *onboardingWorkflow) triggerEarlyKYC(ctx workflow.Context, currency string) {
+	if w.documentID != "" || (w.status != shared.StatusPending && w.status != shared.StatusRemindersActive) {
+		return
+	}
+	w.earlyKYCTriggered = true
+
+	early := workflow.Now(ctx).Add(time.Duration(w.volumePolicy.EarlyDeadlineDays) * 24 * time.Hour)
+	if early.Before(w.deadline) {
+		w.moveDeadline(early)
+	}
+	// Executions started before the reminder timeline start their deadline
+	// timer late; the early deadline must still be enforced.
+	w.startDeadline()
+	w.logger.Info("Volume threshold crossed, requiring early KYC",
+		"currency", currency,
+		"volume", w.processedVolume[currency],
+		"deadline", w.deadline,
+	)
+
+	if w.timelineVersion >= 2 {
+		w.sendEarlyKYCReminder(ctx)
+	} else if _, err := w.sendReminder(ctx, "volumeThreshold", shared.UrgencyUrgent); err != nil {
+		w.logger.Error("Failed to send early KYC reminder", "error", err)
+	}
+
+	if w.volumePolicy.DailyVolumeCap > 0 {
+		w.applyRestriction(ctx, shared.RestrictionStep{
+			Level:          shared.RestrictionVolumeCap,
+			DailyVolumeCap: w.volumePolicy.DailyVolumeCap,
+		})
+	}
+}
+
+// sendEarlyKYCReminder sends the urgent early KYC reminder within the
+// merchant's send window, like the scheduled reminders. When it has to wait
+// for the window it is sent from its own goroutine, so payment events keep
+// being recorded meanwhile, and dropped if documents arrive first.
+func (w *onboardingWorkflow) sendEarlyKYCReminder(ctx workflow.Context) {
+	send := func(ctx workflow.Context) {
+		if _, err := w.sendReminder(ctx, "volumeThreshold", shared.UrgencyUrgent); err != nil {
+			w.logger.Error("Failed to send early KYC reminder", "error", err)
+		}
+	}
+
+	now := workflow.Now(ctx)
+	delay := w.recordedSendTime(ctx, now).Sub(now)
+	if delay <= 0 {
+		send(ctx)
+		return
+	}
+	w.logger.Info("Deferring early KYC reminder to the send window", "delay", delay)
+	workflow.Go(ctx, func(ctx workflow.Context) {
+		if err := workflow.Sleep(ctx, delay); err != nil {
+			return
+		}
+		if w.documentID != "" || (w.status != shared.StatusPending && w.status != shared.StatusRemindersActive) {
+			return
+		}
+		send(ctx)
+	})
+}
@@ -0,0 +1,64 @@
+package workflows
+
+import (
+	"fmt"
+
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// handleReminderResends runs for the lifetime of the workflow, sending an
+// extra reminder for every SignalResendReminder received while documents
+// are outstanding. The regular schedule is unaffected.
+func (w *onboardingWorkflow) handleReminderResends(ctx workflow.Context) {
+	for {
+		var resend shared.ReminderResend
+		w.resendCh.Receive(ctx, &resend)
+
+		if w.repeatedRequest("resend", resend.RequestID) {
+			w.logger.Info("Ignoring repeated reminder resend", "requestId", resend.RequestID)
+			continue
+		}
+		if w.status != shared.StatusRemindersActive || w.documentID != "" || w.deadlinePassed.IsReady() {
+			w.logger.Info("Ignoring reminder resend",
+				"status", w.status,
+			)
+			continue
+		}
+
+		// Each resend is a distinct delivery, so it gets its own idempotency
+		// key; the request ID keeps it stable across signal redeliveries.
+		w.resends++
+		req := w.reminderRequest("manual", shared.UrgencyStandard)
+		req.Sequence = w.resends
+		keyType := fmt.Sprintf("manual-%d", w.resends)
+		if resend.RequestID != "" {
+			keyType = "manual:" + resend.RequestID
+		}
+		req.IdempotencyKey = shared.ReminderIdempotencyKey(w.workflowID, w.firstRunID, keyType+":"+w.reminderChannel)
+
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			if _, err := w.deliverReminder(ctx, req); err != nil {
+				w.logger.Error("Failed to resend reminder", "error", err)
+				return
+			}
+			w.logger.Info("Reminder resent", "reason", resend.Reason)
+		})
+	}
+}
+
+// repeatedRequest reports whether an idempotent signal of the given kind
+// with this request ID was already applied, and records it otherwise.
+// Signals without a request ID are always applied.
+func (w *onboardingWorkflow) repeatedRequest(kind, requestID string) bool {
+	if requestID == "" {
+		return false
+	}
+	key := kind + ":" + requestID
+	if w.handledRequests[key] {
+		return true
+	}
+	w.handledRequests[key] = true
+	return false
+}
@@ -0,0 +1,56 @@
+package workflows
+
+import (
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// indexSearchAttributes keeps the onboarding's search attributes in step
+// with its state for the lifetime of the workflow, so operators can find
+// onboardings by status, country or deadline with a visibility query. It
+// runs on a disconnected context so cancellation is indexed too.
+func (w *onboardingWorkflow) indexSearchAttributes(ctx workflow.Context) {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	for {
+		w.upsertSearchAttributes(ctx)
+		_ = workflow.Await(ctx, func() bool {
+			return w.status != w.indexedStatus || !w.deadline.Equal(w.indexedDeadline) || w.kycAttempts != w.indexedKYCAttempts
+		})
+	}
+}
+
+// upsertSearchAttributes upserts the search attributes that changed since
+// the last call. The merchant's attributes are set on the first call.
+func (w *onboardingWorkflow) upsertSearchAttributes(ctx workflow.Context) {
+	var updates []temporal.SearchAttributeUpdate
+	if !w.indexed {
+		m := w.req.Merchant
+		updates = append(updates, shared.SearchAttrMerchantID.ValueSet(m.MerchantID))
+		if m.Country != "" {
+			updates = append(updates, shared.SearchAttrCountry.ValueSet(m.Country))
+		}
+		if m.BusinessType != "" {
+			updates = append(updates, shared.SearchAttrBusinessType.ValueSet(m.BusinessType))
+		}
+	}
+	if !w.indexed || w.status != w.indexedStatus {
+		updates = append(updates, shared.SearchAttrOnboardingStatus.ValueSet(string(w.status)))
+	}
+	if !w.indexed || !w.deadline.Equal(w.indexedDeadline) {
+		updates = append(updates, shared.SearchAttrDeadline.ValueSet(w.deadline))
+	}
+	if !w.indexed || w.kycAttempts != w.indexedKYCAttempts {
+		updates = append(updates, shared.SearchAttrKYCAttempts.ValueSet(int64(w.kycAttempts)))
+	}
+	if len(updates) == 0 {
+		return
+	}
+
+	w.indexed = true
+	w.indexedStatus, w.indexedDeadline, w.indexedKYCAttempts = w.status, w.deadline, w.kycAttempts
+	if err := workflow.UpsertTypedSearchAttributes(ctx, updates...); err != nil {
+		w.logger.Warn("Failed to upsert search attributes", "error", err)
+	}
+}
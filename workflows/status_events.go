@@ -0,0 +1,86 @@
+package workflows
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"temporal-customer-onboarding/shared"
+)
+
+// startStatusEvents publishes a StatusEvent whenever the status or payment
+// restriction changes, so the portal can follow the onboarding without
+// polling. A watcher queues a snapshot of every change and a publisher sends
+// them in order through the PublishStatusEvent activity, so a slow publish
+// never hides the transitions that happen meanwhile. Both run on a
+// disconnected context so the final CANCELLED event still goes out.
+func (w *onboardingWorkflow) startStatusEvents(ctx workflow.Context) {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	workflow.Go(ctx, w.watchStatus)
+	workflow.Go(ctx, w.publishStatusEvents)
+}
+
+// watchStatus queues a StatusEvent for each change until flushStatusEvents
+// is called.
+func (w *onboardingWorkflow) watchStatus(ctx workflow.Context) {
+	var status shared.OnboardingStatus
+	var restriction shared.RestrictionLevel
+	changed := func() bool { return w.status != status || w.restriction != restriction }
+
+	for {
+		_ = workflow.Await(ctx, func() bool { return changed() || w.statusEventsClosed })
+		if changed() {
+			status, restriction = w.status, w.restriction
+			w.statusSeq++
+			w.statusEvents = append(w.statusEvents, shared.StatusEvent{
+				MerchantID:  w.req.Merchant.MerchantID,
+				WorkflowID:  w.workflowID,
+				RunID:       w.runID,
+				Sequence:    w.statusSeq,
+				Status:      status,
+				Restriction: restriction,
+				Deadline:    w.deadline,
+				At:          workflow.Now(ctx),
+			})
+		}
+		if w.statusEventsClosed {
+			w.statusWatchDone = true
+			return
+		}
+	}
+}
+
+// publishStatusEvents sends queued events one at a time. Publishing is best
+// effort: an event that can't be delivered is logged and dropped, and the
+// next one carries the current state anyway.
+func (w *onboardingWorkflow) publishStatusEvents(ctx workflow.Context) {
+	actCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           w.req.ActivityQueue(),
+		StartToCloseTimeout: 5 * time.Second,
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+	})
+
+	for {
+		_ = workflow.Await(ctx, func() bool { return len(w.statusEvents) > 0 || w.statusWatchDone })
+		if len(w.statusEvents) == 0 {
+			w.statusEventsFlushed = true
+			return
+		}
+		event := w.statusEvents[0]
+		w.statusEvents = w.statusEvents[1:]
+		if err := workflow.ExecuteActivity(actCtx, a.PublishStatusEvent, event).Get(ctx, nil); err != nil {
+			w.logger.Warn("Failed to publish status event", "sequence", event.Sequence, "status", event.Status, "error", err)
+		}
+	}
+}
+
+// flushStatusEvents publishes the final state and waits until every queued
+// event has been sent. Call it once, just before the workflow returns.
+func (w *onboardingWorkflow) flushStatusEvents(ctx workflow.Context) {
+	ctx, _ = workflow.NewDisconnectedContext(ctx)
+	w.statusEventsClosed = true
+	_ = workflow.Await(ctx, func() bool { return w.statusEventsFlushed })
+}
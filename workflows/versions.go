@@ -0,0 +1,57 @@
+package workflows
+
+import "go.temporal.io/sdk/workflow"
+
+// Change IDs for workflow.GetVersion. OnboardingWorkflow runs for up to 90
+// days, so a change to the timers, activities or child workflows it issues
+// breaks running executions on replay unless it is guarded. To change a
+// guarded branch, raise its max version in pinVersions and branch on the
+// new value, keeping the old code for executions pinned to earlier
+// versions; then add a history for the new path to tests/testdata/histories.
+const (
+	versionTypedResult      = "typed-onboarding-result"
+	versionStatusEvents     = "status-events"
+	versionSearchAttributes = "search-attributes"
+	versionEscalationToOps  = "escalation-to-ops"
+
+	// versionReminderTimeline guards the reminder and restriction timeline:
+	// which steps run, in what order, and when.
+	versionReminderTimeline = "reminder-timeline"
+	// versionDeadlineExpiry guards what happens when the deadline passes
+	// without documents: disabling payments and alerting ops.
+	versionDeadlineExpiry = "deadline-expiry"
+	// versionKYC guards identity verification and the activities that follow
+	// its result: lifting restrictions, conditions and notifications.
+	versionKYC = "kyc"
+)
+
+// pinVersions fixes the version of every guarded branch at the start of the
+// workflow, so an execution keeps the behaviour it started with even if a
+// new version is deployed before it reaches the branch. Executions started
+// before a change ID existed get workflow.DefaultVersion.
+func (w *onboardingWorkflow) pinVersions(ctx workflow.Context) {
+	// Executions started before OnboardingResult existed have no marker and
+	// keep returning the legacy result string.
+	w.typedResult = workflow.GetVersion(ctx, versionTypedResult, workflow.DefaultVersion, 1) == 1
+	w.publishStatus = workflow.GetVersion(ctx, versionStatusEvents, workflow.DefaultVersion, 1) == 1
+	w.searchAttributes = workflow.GetVersion(ctx, versionSearchAttributes, workflow.DefaultVersion, 1) == 1
+	// Executions started earlier escalate hard bounces to an empty account
+	// manager address instead of alerting ops.
+	w.escalateToOps = workflow.GetVersion(ctx, versionEscalationToOps, workflow.DefaultVersion, 1) == 1
+
+	// DefaultVersion: LegacyReminderSchedule on sequential timers, with the
+	// deadline timer started after the reminders. 1: the merged reminder and
+	// restriction timeline with an independent deadline timer. 2: also
+	// defers the early KYC reminder to the send window and falls back to
+	// the default volume policy when the requested one is invalid. 3: also
+	// records reminder send times, so holiday calendar updates don't change
+	// them on replay.
+	w.timelineVersion = workflow.GetVersion(ctx, versionReminderTimeline, workflow.DefaultVersion, 3)
+	// DefaultVersion disables payments; 1 also alerts ops.
+	w.expiryVersion = workflow.GetVersion(ctx, versionDeadlineExpiry, workflow.DefaultVersion, 1)
+	// DefaultVersion doesn't alert ops on rejection. 1 does, and lifts
+	// restrictions on approval right away; 2 first waits for restrictions
+	// still being applied. 3 also applies approval conditions before lifting
+	// restrictions.
+	w.kycVersion = workflow.GetVersion(ctx, versionKYC, workflow.DefaultVersion, 3)
+}
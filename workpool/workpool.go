@@ -0,0 +1,64 @@
+// Package workpool runs one call per item of a bulk operation — the CSV
+// import, a batch over running onboardings — with a bounded number of calls
+// in flight and a bounded start rate, so a large run doesn't overwhelm
+// Temporal or the services behind it.
+package workpool
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// Pool configures Run.
+type Pool struct {
+	// Concurrency is the number of calls in flight at once; 0 means 4.
+	Concurrency int
+	// Rate is the maximum number of calls started per second; 0 means
+	// unlimited.
+	Rate float64
+	// Done, if set, is called after each call returns, one at a time, so it
+	// can report progress without locking.
+	Done func(i int)
+}
+
+// Run calls fn for each index in indexes and returns once every call has
+// returned. Calls run on up to p.Concurrency goroutines, so fn must only
+// touch state belonging to i, such as the i-th element of a result slice.
+// If ctx ends before an index's turn, fn is called with the context's error
+// and should record i as not run.
+func (p Pool) Run(ctx context.Context, indexes []int, fn func(i int, err error)) {
+	concurrency := p.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	limit := rate.Inf
+	if p.Rate > 0 {
+		limit = rate.Limit(p.Rate)
+	}
+	limiter := rate.NewLimiter(limit, 1)
+
+	var mu sync.Mutex // Serializes Done.
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < concurrency; n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				fn(i, limiter.Wait(ctx))
+				if p.Done != nil {
+					mu.Lock()
+					p.Done(i)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+	for _, i := range indexes {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+}